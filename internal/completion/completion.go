@@ -0,0 +1,38 @@
+// Package completion finds the {{variable}} being typed in an editor and the names completing it.
+package completion
+
+import "strings"
+
+// VariablePrefix returns what was typed after an unclosed {{ right before caret, caret being
+// a rune offset in text. ok is false when the caret is not inside a variable.
+func VariablePrefix(text string, caret int) (prefix string, ok bool) {
+	runes := []rune(text)
+	if caret < 0 || caret > len(runes) {
+		return "", false
+	}
+
+	before := string(runes[:caret])
+	start := strings.LastIndex(before, "{{")
+	if start < 0 {
+		return "", false
+	}
+
+	prefix = before[start+2:]
+	if strings.ContainsAny(prefix, "{} \t\n") {
+		return "", false
+	}
+
+	return prefix, true
+}
+
+// Match returns the names starting with prefix, ignoring case, leaving out the ones
+// fully typed already.
+func Match(names []string, prefix string) []string {
+	out := make([]string, 0)
+	for _, name := range names {
+		if name != prefix && strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
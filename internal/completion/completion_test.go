@@ -0,0 +1,50 @@
+package completion
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVariablePrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		caret  int
+		want   string
+		wantOk bool
+	}{
+		{name: "just opened", text: "https://{{", caret: 10, want: "", wantOk: true},
+		{name: "partial name", text: "https://{{base", caret: 14, want: "base", wantOk: true},
+		{name: "caret in the middle", text: "{{base}}/users", caret: 4, want: "ba", wantOk: true},
+		{name: "closed variable", text: "{{baseUrl}}/users", caret: 17, wantOk: false},
+		{name: "no variable", text: "https://example.com", caret: 19, wantOk: false},
+		{name: "space after braces", text: "{\"a\": {{ b", caret: 10, wantOk: false},
+		{name: "multi byte text", text: "héllo {{to", caret: 10, want: "to", wantOk: true},
+		{name: "caret out of range", text: "{{a", caret: 10, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := VariablePrefix(tt.text, tt.caret)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("VariablePrefix() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	names := []string{"baseUrl", "basePath", "token", "timeNow"}
+
+	if got, want := Match(names, "BASE"), []string{"baseUrl", "basePath"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Match() = %v, want %v", got, want)
+	}
+
+	if got := Match(names, "token"); len(got) != 0 {
+		t.Errorf("expected a fully typed name to be left out, got %v", got)
+	}
+
+	if got := Match(names, ""); len(got) != len(names) {
+		t.Errorf("expected all the names for an empty prefix, got %v", got)
+	}
+}
@@ -9,6 +9,7 @@ const (
 	KindPreferences   = "Preferences"
 	KindCollection    = "Collection"
 	KindProtoFileList = "ProtoFileList"
+	KindAuthProfile   = "AuthProfile"
 )
 
 type MetaData struct {
@@ -37,3 +38,36 @@ func CompareKeyValues(a, b []KeyValue) bool {
 
 	return true
 }
+
+// CloneKeyValues returns a copy of the given slice that does not share its backing array
+func CloneKeyValues(kvs []KeyValue) []KeyValue {
+	if kvs == nil {
+		return nil
+	}
+
+	clone := make([]KeyValue, len(kvs))
+	copy(clone, kvs)
+	return clone
+}
+
+// SetAllEnabled enables or disables all the given key values in place
+func SetAllEnabled(kvs []KeyValue, enabled bool) {
+	for i := range kvs {
+		kvs[i].Enable = enabled
+	}
+}
+
+// AllEnabled returns true if there is at least one key value and all of them are enabled
+func AllEnabled(kvs []KeyValue) bool {
+	if len(kvs) == 0 {
+		return false
+	}
+
+	for _, kv := range kvs {
+		if !kv.Enable {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "testing"
+
+func TestSetAllEnabled(t *testing.T) {
+	kvs := []KeyValue{
+		{Key: "a", Enable: true},
+		{Key: "b", Enable: false},
+		{Key: "c", Enable: true},
+	}
+
+	if AllEnabled(kvs) {
+		t.Errorf("expected AllEnabled to be false")
+	}
+
+	SetAllEnabled(kvs, true)
+	for _, kv := range kvs {
+		if !kv.Enable {
+			t.Errorf("expected %s to be enabled", kv.Key)
+		}
+	}
+
+	if !AllEnabled(kvs) {
+		t.Errorf("expected AllEnabled to be true")
+	}
+
+	SetAllEnabled(kvs, false)
+	for _, kv := range kvs {
+		if kv.Enable {
+			t.Errorf("expected %s to be disabled", kv.Key)
+		}
+	}
+
+	if AllEnabled(nil) {
+		t.Errorf("expected AllEnabled to be false for empty list")
+	}
+}
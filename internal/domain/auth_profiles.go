@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+)
+
+// AuthProfile is a named auth configuration that requests can reference by id
+// instead of repeating the same credentials.
+type AuthProfile struct {
+	ApiVersion string   `yaml:"apiVersion"`
+	Kind       string   `yaml:"kind"`
+	MetaData   MetaData `yaml:"metadata"`
+	Spec       Auth     `yaml:"spec"`
+	FilePath   string   `yaml:"-"`
+}
+
+func NewAuthProfile(name string) *AuthProfile {
+	return &AuthProfile{
+		ApiVersion: ApiVersion,
+		Kind:       KindAuthProfile,
+		MetaData: MetaData{
+			ID:   uuid.NewString(),
+			Name: name,
+		},
+		Spec: Auth{
+			Type: AuthTypeNone,
+		},
+	}
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
 
 type Collection struct {
 	ApiVersion string   `yaml:"apiVersion"`
@@ -13,6 +17,8 @@ type Collection struct {
 
 type ColSpec struct {
 	Requests []*Request `yaml:"requests"`
+	// Order is the position of the collection in the sidebar.
+	Order int `yaml:"order,omitempty"`
 }
 
 func (c *Collection) Clone() *Collection {
@@ -25,6 +31,7 @@ func (c *Collection) Clone() *Collection {
 		},
 		Spec: ColSpec{
 			Requests: make([]*Request, len(c.Spec.Requests)),
+			Order:    c.Spec.Order,
 		},
 		FilePath: c.FilePath,
 	}
@@ -72,3 +79,25 @@ func (c *Collection) FindRequestByID(id string) *Request {
 	}
 	return nil
 }
+
+// SortRequests sorts requests by their sidebar order, falling back to the name
+// for requests that share the same order.
+func SortRequests(requests []*Request) {
+	sort.SliceStable(requests, func(i, j int) bool {
+		if requests[i].MetaData.Order != requests[j].MetaData.Order {
+			return requests[i].MetaData.Order < requests[j].MetaData.Order
+		}
+		return requests[i].MetaData.Name < requests[j].MetaData.Name
+	})
+}
+
+// SortCollections sorts collections by their sidebar order, falling back to the name
+// for collections that share the same order.
+func SortCollections(collections []*Collection) {
+	sort.SliceStable(collections, func(i, j int) bool {
+		if collections[i].Spec.Order != collections[j].Spec.Order {
+			return collections[i].Spec.Order < collections[j].Spec.Order
+		}
+		return collections[i].MetaData.Name < collections[j].MetaData.Name
+	})
+}
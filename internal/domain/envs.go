@@ -14,6 +14,8 @@ type Environment struct {
 
 type EnvSpec struct {
 	Values []KeyValue `yaml:"values"`
+	// DefaultAuth is used by the requests that have no auth of their own.
+	DefaultAuth *Auth `yaml:"defaultAuth,omitempty"`
 }
 
 func (e *EnvSpec) Clone() EnvSpec {
@@ -21,6 +23,11 @@ func (e *EnvSpec) Clone() EnvSpec {
 		Values: make([]KeyValue, len(e.Values)),
 	}
 
+	if e.DefaultAuth != nil {
+		auth := e.DefaultAuth.Clone()
+		clone.DefaultAuth = &auth
+	}
+
 	for i, v := range e.Values {
 		clone.Values[i] = KeyValue{
 			ID:     uuid.NewString(),
@@ -48,6 +55,18 @@ func NewEnvironment(name string) *Environment {
 	}
 }
 
+func CompareEnvSpecs(a, b EnvSpec) bool {
+	if !CompareKeyValues(a.Values, b.Values) {
+		return false
+	}
+
+	if a.DefaultAuth == nil || b.DefaultAuth == nil {
+		return a.DefaultAuth == b.DefaultAuth
+	}
+
+	return CompareAuth(*a.DefaultAuth, *b.DefaultAuth)
+}
+
 func CompareEnvValue(a, b KeyValue) bool {
 	// compare length of the values
 	if len(a.Key) != len(b.Key) || len(a.Value) != len(b.Value) || len(a.ID) != len(b.ID) {
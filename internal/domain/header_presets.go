@@ -0,0 +1,30 @@
+package domain
+
+import "github.com/google/uuid"
+
+// HeaderPreset is a commonly used header offered by the headers quick-insert menu.
+type HeaderPreset struct {
+	Key   string
+	Value string
+}
+
+// HeaderPresets is the curated list of headers offered by the quick-insert menu.
+var HeaderPresets = []HeaderPreset{
+	{Key: "Authorization", Value: "Bearer "},
+	{Key: "Content-Type", Value: "application/json"},
+	{Key: "Accept", Value: "application/json"},
+	{Key: "Accept-Encoding", Value: "gzip, deflate"},
+	{Key: "X-Request-Id", Value: ""},
+	{Key: "traceparent", Value: "00-<trace-id>-<span-id>-01"},
+	{Key: "tracestate", Value: ""},
+}
+
+// KeyValue returns the preset as a new disabled key value, so it is not sent until the user fills and enables it.
+func (p HeaderPreset) KeyValue() KeyValue {
+	return KeyValue{
+		ID:     uuid.NewString(),
+		Key:    p.Key,
+		Value:  p.Value,
+		Enable: false,
+	}
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "testing"
+
+func TestHeaderPresets(t *testing.T) {
+	want := []string{"Authorization", "Content-Type", "Accept", "Accept-Encoding", "X-Request-Id", "traceparent", "tracestate"}
+	if len(HeaderPresets) != len(want) {
+		t.Fatalf("expected %d presets, got %d", len(want), len(HeaderPresets))
+	}
+
+	for i, key := range want {
+		if HeaderPresets[i].Key != key {
+			t.Errorf("preset %d: expected %s, got %s", i, key, HeaderPresets[i].Key)
+		}
+	}
+}
+
+func TestHeaderPreset_KeyValue(t *testing.T) {
+	a := HeaderPresets[0].KeyValue()
+	b := HeaderPresets[0].KeyValue()
+
+	if a.Enable {
+		t.Errorf("expected the inserted header to start disabled")
+	}
+
+	if a.Key != "Authorization" || a.Value != "Bearer " {
+		t.Errorf("unexpected header %s=%s", a.Key, a.Value)
+	}
+
+	if a.ID == "" || a.ID == b.ID {
+		t.Errorf("expected every inserted header to get its own id")
+	}
+}
@@ -12,6 +12,48 @@ type Preferences struct {
 type PrefSpec struct {
 	DarkMode            bool                `yaml:"darkMode"`
 	SelectedEnvironment SelectedEnvironment `yaml:"selectedEnvironment"`
+
+	// VerticalRequestLayout stacks the request and response panes on top of each other
+	// instead of showing them side by side.
+	VerticalRequestLayout bool `yaml:"verticalRequestLayout,omitempty"`
+
+	// RequestTimeoutMilliseconds is the default timeout for sending requests, zero means no timeout.
+	RequestTimeoutMilliseconds int `yaml:"requestTimeoutMilliseconds,omitempty"`
+
+	// MaxRenderBytes caps the size of the response body shown in the response pane, zero means
+	// DefaultMaxRenderBytes and a negative value means no limit. The whole body is still used for
+	// copying and snapshots.
+	MaxRenderBytes int `yaml:"maxRenderBytes,omitempty"`
+
+	// InjectTraceparent attaches a freshly generated W3C traceparent header to every request
+	// that does not set one, along with Tracestate when it is not empty.
+	InjectTraceparent bool   `yaml:"injectTraceparent,omitempty"`
+	Tracestate        string `yaml:"tracestate,omitempty"`
+
+	// WarnRequestBytes and WarnLatencyMs are advisory thresholds, a response gets a warning when
+	// the request body was bigger or the response took longer. Zero disables the check.
+	WarnRequestBytes int `yaml:"warnRequestBytes,omitempty"`
+	WarnLatencyMs    int `yaml:"warnLatencyMs,omitempty"`
+
+	// DebugWireCapture appends the raw requests and responses to WireCaptureFile, for debugging
+	// protocol level issues. The capture holds credentials, so it is meant to be turned on briefly.
+	DebugWireCapture bool   `yaml:"debugWireCapture,omitempty"`
+	WireCaptureFile  string `yaml:"wireCaptureFile,omitempty"`
+}
+
+// DefaultMaxRenderBytes is the render cap used when MaxRenderBytes is not set.
+const DefaultMaxRenderBytes = 1 << 20
+
+// RenderLimit returns the number of bytes the response pane renders, zero means no limit.
+func (p PrefSpec) RenderLimit() int {
+	switch {
+	case p.MaxRenderBytes < 0:
+		return 0
+	case p.MaxRenderBytes == 0:
+		return DefaultMaxRenderBytes
+	default:
+		return p.MaxRenderBytes
+	}
 }
 
 type SelectedEnvironment struct {
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestPrefSpec_RenderLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxRenderBytes int
+		want           int
+	}{
+		{name: "unset uses the default", maxRenderBytes: 0, want: DefaultMaxRenderBytes},
+		{name: "negative means no limit", maxRenderBytes: -1, want: 0},
+		{name: "explicit limit", maxRenderBytes: 4096, want: 4096},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := PrefSpec{MaxRenderBytes: tt.maxRenderBytes}
+			if got := spec.RenderLimit(); got != tt.want {
+				t.Errorf("RenderLimit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreferences_ExistingFileUsesDefaultRenderLimit(t *testing.T) {
+	// preferences written before maxRenderBytes existed
+	data := []byte("apiVersion: v1\nkind: Preferences\nspec:\n  darkMode: true\n")
+
+	prefs := &Preferences{}
+	if err := yaml.Unmarshal(data, prefs); err != nil {
+		t.Fatalf("failed to unmarshal preferences: %v", err)
+	}
+
+	if got := prefs.Spec.RenderLimit(); got != DefaultMaxRenderBytes {
+		t.Errorf("RenderLimit() = %d, want %d", got, DefaultMaxRenderBytes)
+	}
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"encoding/json"
+	"slices"
 	"strings"
 	"time"
 
@@ -49,9 +50,15 @@ type Request struct {
 }
 
 type RequestMeta struct {
-	ID   string `yaml:"id"`
-	Name string `yaml:"name"`
-	Type string `yaml:"type"`
+	ID          string   `yaml:"id"`
+	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type"`
+	Description string   `yaml:"description,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+	// Color is a #rrggbb color shown next to the request in the sidebar.
+	Color string `yaml:"color,omitempty"`
+	// Order is the position of the request among its siblings in the sidebar.
+	Order int `yaml:"order,omitempty"`
 }
 
 type RequestSpec struct {
@@ -75,8 +82,18 @@ type HTTPRequestSpec struct {
 
 	LastUsedEnvironment LastUsedEnvironment `yaml:"lastUsedEnvironment"`
 
+	// EnvironmentOverride is the id of the environment the request runs against, whatever environment is selected.
+	EnvironmentOverride string `yaml:"environmentOverride,omitempty"`
+
 	Request   *HTTPRequest   `yaml:"request"`
 	Responses []HTTPResponse `yaml:"responses"`
+
+	// Snapshot is the expected response, when set every response is compared against it.
+	Snapshot *ResponseSnapshot `yaml:"snapshot,omitempty"`
+}
+
+type ResponseSnapshot struct {
+	Body string `yaml:"body"`
 }
 
 type LastUsedEnvironment struct {
@@ -91,6 +108,22 @@ func (h *HTTPRequestSpec) Clone() *HTTPRequestSpec {
 		clone.Request = h.Request.Clone()
 	}
 
+	if h.Responses != nil {
+		clone.Responses = make([]HTTPResponse, len(h.Responses))
+		for i, r := range h.Responses {
+			clone.Responses[i] = HTTPResponse{
+				Headers: CloneKeyValues(r.Headers),
+				Body:    r.Body,
+				Cookies: CloneKeyValues(r.Cookies),
+			}
+		}
+	}
+
+	if h.Snapshot != nil {
+		snapshot := *h.Snapshot
+		clone.Snapshot = &snapshot
+	}
+
 	return &clone
 }
 
@@ -104,6 +137,9 @@ type HTTPRequest struct {
 
 	Auth Auth `yaml:"auth"`
 
+	// Variables are local to the request, they take precedence over the environment variables with the same name.
+	Variables []KeyValue `yaml:"variables,omitempty"`
+
 	PreRequest  PreRequest  `yaml:"preRequest"`
 	PostRequest PostRequest `yaml:"postRequest"`
 }
@@ -130,6 +166,16 @@ type Body struct {
 
 func (b *Body) Clone() *Body {
 	clone := *b
+	clone.URLEncoded = CloneKeyValues(b.URLEncoded)
+
+	if b.FormData.Fields != nil {
+		clone.FormData.Fields = make([]FormField, len(b.FormData.Fields))
+		for i, f := range b.FormData.Fields {
+			clone.FormData.Fields[i] = f
+			clone.FormData.Fields[i].Files = append([]string(nil), f.Files...)
+		}
+	}
+
 	return &clone
 }
 
@@ -156,13 +202,27 @@ const (
 	AuthTypeBasic  = "basic"
 	AuthTypeToken  = "token"
 	AuthTypeAPIKey = "apiKey"
+	// AuthTypeProfile uses the auth of the AuthProfile referenced by ProfileID.
+	AuthTypeProfile = "profile"
+	AuthTypeJWT     = "jwt"
+	// AuthTypeAWSSigV4 signs the request with AWS Signature Version 4.
+	AuthTypeAWSSigV4 = "awsSigV4"
+	// AuthTypeCommand sends the output of a command, such as a cli printing an access token, as a header.
+	AuthTypeCommand = "command"
+
+	JWTAlgorithmHS256 = "HS256"
+	JWTAlgorithmRS256 = "RS256"
 )
 
 type Auth struct {
-	Type       string      `yaml:"type"`
-	BasicAuth  *BasicAuth  `yaml:"basicAuth,omitempty"`
-	TokenAuth  *TokenAuth  `yaml:"tokenAuth,omitempty"`
-	APIKeyAuth *APIKeyAuth `yaml:"apiKey,omitempty"`
+	Type         string        `yaml:"type"`
+	BasicAuth    *BasicAuth    `yaml:"basicAuth,omitempty"`
+	TokenAuth    *TokenAuth    `yaml:"tokenAuth,omitempty"`
+	APIKeyAuth   *APIKeyAuth   `yaml:"apiKey,omitempty"`
+	JWTAuth      *JWTAuth      `yaml:"jwt,omitempty"`
+	AWSSigV4Auth *AWSSigV4Auth `yaml:"awsSigV4,omitempty"`
+	CommandAuth  *CommandAuth  `yaml:"command,omitempty"`
+	ProfileID    string        `yaml:"profileId,omitempty"`
 }
 
 type APIKeyAuth struct {
@@ -184,6 +244,18 @@ func (a *Auth) Clone() Auth {
 		clone.APIKeyAuth = a.APIKeyAuth.Clone()
 	}
 
+	if a.JWTAuth != nil {
+		clone.JWTAuth = a.JWTAuth.Clone()
+	}
+
+	if a.AWSSigV4Auth != nil {
+		clone.AWSSigV4Auth = a.AWSSigV4Auth.Clone()
+	}
+
+	if a.CommandAuth != nil {
+		clone.CommandAuth = a.CommandAuth.Clone()
+	}
+
 	return clone
 }
 
@@ -207,6 +279,21 @@ func (a *APIKeyAuth) Clone() *APIKeyAuth {
 	}
 }
 
+func (a *JWTAuth) Clone() *JWTAuth {
+	clone := *a
+	return &clone
+}
+
+func (a *AWSSigV4Auth) Clone() *AWSSigV4Auth {
+	clone := *a
+	return &clone
+}
+
+func (a *CommandAuth) Clone() *CommandAuth {
+	clone := *a
+	return &clone
+}
+
 type BasicAuth struct {
 	Username string `yaml:"username"`
 	Password string `yaml:"password"`
@@ -216,6 +303,53 @@ type TokenAuth struct {
 	Token string `yaml:"token"`
 }
 
+// JWTAuth signs a token at send time and sends it as a bearer token.
+type JWTAuth struct {
+	// Algorithm is either HS256 or RS256.
+	Algorithm string `yaml:"algorithm"`
+	// Secret is the HMAC secret for HS256 or the PEM encoded private key for RS256.
+	Secret string `yaml:"secret"`
+	// Claims is a JSON object, variables are applied to it before signing.
+	Claims string `yaml:"claims"`
+	// TTLSeconds sets the exp claim relative to the time of signing, zero leaves it out.
+	TTLSeconds int `yaml:"ttlSeconds"`
+}
+
+// NewJWTAuth returns the JWT auth a request starts with, signing with HS256.
+func NewJWTAuth() *JWTAuth {
+	return &JWTAuth{
+		Algorithm: JWTAlgorithmHS256,
+		Claims:    "{}",
+	}
+}
+
+type AWSSigV4Auth struct {
+	AccessKey    string `yaml:"accessKey"`
+	SecretKey    string `yaml:"secretKey"`
+	SessionToken string `yaml:"sessionToken,omitempty"`
+	Region       string `yaml:"region"`
+	Service      string `yaml:"service"`
+}
+
+// CommandAuth runs Command at send time and sends its trimmed output as the Header header,
+// after Prefix. The output is reused for TTLSeconds, zero runs the command on every send.
+// Command runs without a shell but with the privileges of the user; variables are not
+// substituted into it, they are passed to it as CHAPAR_<name> environment variables.
+type CommandAuth struct {
+	Command    string `yaml:"command"`
+	Header     string `yaml:"header"`
+	Prefix     string `yaml:"prefix,omitempty"`
+	TTLSeconds int    `yaml:"ttlSeconds"`
+}
+
+// NewCommandAuth returns the command auth a request starts with, sending the output as a bearer token.
+func NewCommandAuth() *CommandAuth {
+	return &CommandAuth{
+		Header: "Authorization",
+		Prefix: "Bearer ",
+	}
+}
+
 type HTTPResponse struct {
 	Headers []KeyValue `yaml:"headers"`
 	Body    string     `yaml:"body"`
@@ -284,20 +418,83 @@ type SShTunnel struct {
 func (r *Request) Clone() *Request {
 	clone := *r
 	clone.MetaData.ID = uuid.NewString()
+	clone.MetaData.Tags = slices.Clone(r.MetaData.Tags)
 	clone.Spec = *r.Spec.Clone()
 	return &clone
 }
 
+// ApplyEdits copies the spec and the editable metadata of edited into the request, without
+// sharing any reference with it. The id, name, file path and collection of the request are kept.
+func (r *Request) ApplyEdits(edited *Request) {
+	clone := edited.Clone()
+	r.Spec = clone.Spec
+	r.MetaData.Description = clone.MetaData.Description
+	r.MetaData.Tags = clone.MetaData.Tags
+	r.MetaData.Color = clone.MetaData.Color
+}
+
+// HasTag reports whether the request is tagged with tag, ignoring case.
+func (r *Request) HasTag(tag string) bool {
+	return ContainsTag(r.MetaData.Tags, tag)
+}
+
+// ContainsTag reports whether tags holds tag, ignoring case.
+func ContainsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTags splits a comma separated list of tags, dropping a leading '#',
+// empty entries and duplicates.
+func ParseTags(text string) []string {
+	tags := make([]string, 0)
+	for _, t := range strings.Split(text, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "#")
+		if t == "" || slices.Contains(tags, t) {
+			continue
+		}
+		tags = append(tags, t)
+	}
+	return tags
+}
+
 func (r *HTTPRequest) Clone() *HTTPRequest {
 	clone := *r
 
+	clone.Headers = CloneKeyValues(r.Headers)
+	clone.PathParams = CloneKeyValues(r.PathParams)
+	clone.QueryParams = CloneKeyValues(r.QueryParams)
+	clone.Variables = CloneKeyValues(r.Variables)
+	clone.Body = *r.Body.Clone()
+
 	if r.Auth != (Auth{}) {
 		clone.Auth = r.Auth.Clone()
 	}
 
+	clone.PreRequest = r.PreRequest.Clone()
 	return &clone
 }
 
+func (p *PreRequest) Clone() PreRequest {
+	clone := *p
+	if p.SShTunnel != nil {
+		tunnel := *p.SShTunnel
+		tunnel.Flags = append([]string(nil), p.SShTunnel.Flags...)
+		clone.SShTunnel = &tunnel
+	}
+
+	if p.KubernetesTunnel != nil {
+		tunnel := *p.KubernetesTunnel
+		clone.KubernetesTunnel = &tunnel
+	}
+
+	return clone
+}
+
 func (r *RequestSpec) Clone() *RequestSpec {
 	clone := *r
 	if r.GRPC != nil {
@@ -341,6 +538,10 @@ func CompareRequests(a, b *Request) bool {
 		return false
 	}
 
+	if a.MetaData.Description != b.MetaData.Description || !slices.Equal(a.MetaData.Tags, b.MetaData.Tags) || a.MetaData.Color != b.MetaData.Color || a.MetaData.Order != b.MetaData.Order {
+		return false
+	}
+
 	if !CompareGRPCRequestSpecs(a.Spec.GRPC, b.Spec.GRPC) {
 		return false
 	}
@@ -368,7 +569,7 @@ func CompareGRPCRequestSpecs(a, b *GRPCRequestSpec) bool {
 }
 
 func CompareHTTPRequestSpecs(a, b *HTTPRequestSpec) bool {
-	if a.Method != b.Method || a.URL != b.URL {
+	if a.Method != b.Method || a.URL != b.URL || a.EnvironmentOverride != b.EnvironmentOverride {
 		return false
 	}
 
@@ -386,6 +587,10 @@ func CompareHTTPRequestSpecs(a, b *HTTPRequestSpec) bool {
 		}
 	}
 
+	if (a.Snapshot == nil) != (b.Snapshot == nil) || (a.Snapshot != nil && *a.Snapshot != *b.Snapshot) {
+		return false
+	}
+
 	return true
 }
 
@@ -414,6 +619,10 @@ func CompareHTTPRequests(a, b *HTTPRequest) bool {
 		return false
 	}
 
+	if !CompareKeyValues(a.Variables, b.Variables) {
+		return false
+	}
+
 	if !CompareFormData(a.Body.FormData, b.Body.FormData) {
 		return false
 	}
@@ -479,7 +688,7 @@ func CompareBody(a, b Body) bool {
 
 func CompareAuth(a, b Auth) bool {
 
-	if a.Type != b.Type {
+	if a.Type != b.Type || a.ProfileID != b.ProfileID {
 		return false
 	}
 
@@ -495,6 +704,18 @@ func CompareAuth(a, b Auth) bool {
 		return false
 	}
 
+	if !CompareJWTAuth(a.JWTAuth, b.JWTAuth) {
+		return false
+	}
+
+	if !CompareAWSSigV4Auth(a.AWSSigV4Auth, b.AWSSigV4Auth) {
+		return false
+	}
+
+	if !CompareCommandAuth(a.CommandAuth, b.CommandAuth) {
+		return false
+	}
+
 	return true
 }
 
@@ -546,6 +767,42 @@ func CompareAPIKey(a, b *APIKeyAuth) bool {
 	return true
 }
 
+func CompareJWTAuth(a, b *JWTAuth) bool {
+	if a == nil && b == nil {
+		return true
+	}
+
+	if a == nil || b == nil {
+		return false
+	}
+
+	return *a == *b
+}
+
+func CompareAWSSigV4Auth(a, b *AWSSigV4Auth) bool {
+	if a == nil && b == nil {
+		return true
+	}
+
+	if a == nil || b == nil {
+		return false
+	}
+
+	return *a == *b
+}
+
+func CompareCommandAuth(a, b *CommandAuth) bool {
+	if a == nil && b == nil {
+		return true
+	}
+
+	if a == nil || b == nil {
+		return false
+	}
+
+	return *a == *b
+}
+
 func CompareHTTPResponses(a, b HTTPResponse) bool {
 	if IsHTTPResponseEmpty(a) && IsHTTPResponseEmpty(b) {
 		return true
@@ -778,5 +1035,11 @@ type HTTPResponseDetail struct {
 	Duration   time.Duration
 	Size       int
 
+	// RemainingDeadline is how much of the request timeout was left, zero when there is no timeout.
+	RemainingDeadline time.Duration
+
+	// TraceID is the trace id of the injected traceparent header, empty when none was injected.
+	TraceID string
+
 	Error error
 }
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "empty", text: "", want: []string{}},
+		{name: "single", text: "smoke", want: []string{"smoke"}},
+		{name: "trims spaces and hashes", text: " #smoke , regression,#wip ", want: []string{"smoke", "regression", "wip"}},
+		{name: "drops empty and duplicates", text: "smoke,,smoke, ", want: []string{"smoke"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseTags(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTags(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequest_Tags(t *testing.T) {
+	req := NewRequest("tagged")
+	req.MetaData.Tags = ParseTags("smoke, WIP")
+
+	if !req.HasTag("smoke") || !req.HasTag("wip") {
+		t.Errorf("expected request to have tags smoke and wip, got %v", req.MetaData.Tags)
+	}
+
+	if req.HasTag("regression") {
+		t.Errorf("did not expect request to have tag regression")
+	}
+
+	clone := req.Clone()
+	clone.MetaData.Tags[0] = "changed"
+	if req.MetaData.Tags[0] != "smoke" {
+		t.Errorf("mutating the clone changed the original tags to %v", req.MetaData.Tags)
+	}
+}
+
+func TestRequest_ApplyEdits(t *testing.T) {
+	req := NewRequest("stored")
+	edited := req.Clone()
+	edited.MetaData.Name = "renamed in the editor"
+	edited.MetaData.Description = "Checks the health endpoint."
+	edited.MetaData.Tags = []string{"smoke"}
+	edited.MetaData.Color = "#ff0000"
+	edited.Spec.HTTP.URL = "https://example.com/health"
+
+	id := req.MetaData.ID
+	req.ApplyEdits(edited)
+
+	if req.MetaData.ID != id || req.MetaData.Name != "stored" {
+		t.Errorf("expected the id and name to be kept, got %s %s", req.MetaData.ID, req.MetaData.Name)
+	}
+
+	if req.MetaData.Description != edited.MetaData.Description {
+		t.Errorf("expected description %q, got %q", edited.MetaData.Description, req.MetaData.Description)
+	}
+
+	if !reflect.DeepEqual(req.MetaData.Tags, edited.MetaData.Tags) || req.MetaData.Color != edited.MetaData.Color {
+		t.Errorf("expected tags and color to be applied, got %v %s", req.MetaData.Tags, req.MetaData.Color)
+	}
+
+	if req.Spec.HTTP.URL != edited.Spec.HTTP.URL {
+		t.Errorf("expected url %q, got %q", edited.Spec.HTTP.URL, req.Spec.HTTP.URL)
+	}
+
+	// the request must not share references with the editor copy
+	edited.MetaData.Tags[0] = "changed"
+	edited.Spec.HTTP.Request.Headers[0].Value = "changed"
+	if req.MetaData.Tags[0] != "smoke" || req.Spec.HTTP.Request.Headers[0].Value == "changed" {
+		t.Errorf("expected the applied edits to be a copy")
+	}
+}
@@ -0,0 +1,128 @@
+// Package palette holds the commands of the command palette and the fuzzy matching used to filter them.
+package palette
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type Command struct {
+	Title string
+	Run   func()
+}
+
+// Palette keeps the commands matching the current query and the selected one.
+type Palette struct {
+	commands []Command
+	query    string
+
+	results  []Command
+	selected int
+}
+
+func New(commands []Command) *Palette {
+	p := &Palette{}
+	p.SetCommands(commands)
+	return p
+}
+
+func (p *Palette) SetCommands(commands []Command) {
+	p.commands = commands
+	p.SetQuery(p.query)
+}
+
+func (p *Palette) SetQuery(query string) {
+	p.query = query
+	p.results = Filter(p.commands, query)
+	p.selected = 0
+}
+
+func (p *Palette) Results() []Command {
+	return p.results
+}
+
+func (p *Palette) Selected() int {
+	return p.selected
+}
+
+// Select sets the selected result, it is kept in the range of the results.
+func (p *Palette) Select(index int) {
+	p.selected = max(0, min(index, len(p.results)-1))
+}
+
+// Move moves the selection by delta, wrapping around the results.
+func (p *Palette) Move(delta int) {
+	if len(p.results) == 0 {
+		return
+	}
+
+	p.selected = ((p.selected+delta)%len(p.results) + len(p.results)) % len(p.results)
+}
+
+// Execute runs the selected command, it reports false when nothing matches the query.
+func (p *Palette) Execute() bool {
+	if len(p.results) == 0 {
+		return false
+	}
+
+	if cmd := p.results[p.selected]; cmd.Run != nil {
+		cmd.Run()
+	}
+	return true
+}
+
+// Filter returns the commands fuzzy matching query, best matches first.
+// Commands with the same score keep their order.
+func Filter(commands []Command, query string) []Command {
+	type scored struct {
+		cmd   Command
+		score int
+	}
+
+	matches := make([]scored, 0, len(commands))
+	for _, cmd := range commands {
+		if score, ok := Match(query, cmd.Title); ok {
+			matches = append(matches, scored{cmd: cmd, score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	out := make([]Command, len(matches))
+	for i, m := range matches {
+		out[i] = m.cmd
+	}
+	return out
+}
+
+// Match reports whether the characters of query appear in order in text, ignoring case and spaces in the query.
+// The score favors characters matched in a row and at the start of words.
+func Match(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	t := []rune(strings.ToLower(text))
+
+	score, qi := 0, 0
+	consecutive := false
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			consecutive = false
+			continue
+		}
+
+		score++
+		if consecutive {
+			score += 2
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 3
+		}
+
+		consecutive = true
+		qi++
+	}
+
+	return score, qi == len(q)
+}
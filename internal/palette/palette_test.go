@@ -0,0 +1,91 @@
+package palette
+
+import (
+	"reflect"
+	"testing"
+)
+
+func titles(commands []Command) []string {
+	out := make([]string, len(commands))
+	for i, c := range commands {
+		out[i] = c.Title
+	}
+	return out
+}
+
+func TestFilter(t *testing.T) {
+	commands := []Command{
+		{Title: "Open request: Get users"},
+		{Title: "Open request: Create user"},
+		{Title: "Switch environment: Staging"},
+		{Title: "Send request"},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "empty query keeps every command",
+			query: "",
+			want:  titles(commands),
+		},
+		{
+			name:  "fuzzy match across words",
+			query: "swst",
+			want:  []string{"Switch environment: Staging"},
+		},
+		{
+			name:  "case insensitive",
+			query: "USERS",
+			want:  []string{"Open request: Get users"},
+		},
+		{
+			name:  "word starts rank first",
+			query: "sr",
+			want:  []string{"Send request", "Switch environment: Staging", "Open request: Get users", "Open request: Create user"},
+		},
+		{
+			name:  "no match",
+			query: "xyz",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := titles(Filter(commands, tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPalette_Execute(t *testing.T) {
+	var ran []string
+	command := func(title string) Command {
+		return Command{Title: title, Run: func() { ran = append(ran, title) }}
+	}
+
+	p := New([]Command{command("Open request: Get users"), command("Open request: Get orders"), command("Send request")})
+
+	p.SetQuery("get")
+	p.Move(1)
+	if !p.Execute() {
+		t.Fatalf("expected a command to run")
+	}
+
+	p.Move(1)
+	p.Execute()
+
+	want := []string{"Open request: Get orders", "Open request: Get users"}
+	if !reflect.DeepEqual(ran, want) {
+		t.Errorf("expected %v to run, got %v", want, ran)
+	}
+
+	p.SetQuery("nothing matches")
+	if p.Execute() {
+		t.Errorf("expected nothing to run without results")
+	}
+}
@@ -20,6 +20,7 @@ const (
 	collectionsDir  = "collections"
 	requestsDir     = "requests"
 	preferencesDir  = "preferences"
+	authProfilesDir = "auth-profiles"
 )
 
 var _ Repository = &Filesystem{}
@@ -270,6 +271,76 @@ func (f *Filesystem) DeleteEnvironment(env *domain.Environment) error {
 	return os.Remove(env.FilePath)
 }
 
+func (f *Filesystem) LoadAuthProfiles() ([]*domain.AuthProfile, error) {
+	dir, err := f.GetAuthProfilesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*domain.AuthProfile, 0)
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		filePath := path.Join(dir, file.Name())
+
+		profile, err := LoadFromYaml[domain.AuthProfile](filePath)
+		if err != nil {
+			return nil, err
+		}
+		profile.FilePath = filePath
+		out = append(out, profile)
+	}
+
+	return out, nil
+}
+
+func (f *Filesystem) GetAuthProfilesDir() (string, error) {
+	dir, err := CreateConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	profilesDir := path.Join(dir, authProfilesDir)
+	if err := makeDir(profilesDir); err != nil {
+		return "", err
+	}
+
+	return profilesDir, nil
+}
+
+func (f *Filesystem) UpdateAuthProfile(profile *domain.AuthProfile) error {
+	if profile.FilePath == "" {
+		// this is a new profile
+		fp, err := f.GetNewAuthProfileFilePath(profile.MetaData.Name)
+		if err != nil {
+			return err
+		}
+		profile.FilePath = fp.Path
+	}
+
+	return SaveToYaml(profile.FilePath, profile)
+}
+
+func (f *Filesystem) DeleteAuthProfile(profile *domain.AuthProfile) error {
+	return os.Remove(profile.FilePath)
+}
+
+func (f *Filesystem) GetNewAuthProfileFilePath(name string) (*FilePath, error) {
+	dir, err := f.GetAuthProfilesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return getNewFilePath(dir, name), nil
+}
+
 func (f *Filesystem) ReadPreferencesData() (*domain.Preferences, error) {
 	dir, err := GetConfigDir()
 	if err != nil {
@@ -32,6 +32,12 @@ type Repository interface {
 	UpdateRequest(request *domain.Request) error
 	DeleteRequest(request *domain.Request) error
 	GetNewRequestFilePath(name string) (*FilePath, error)
+
+	LoadAuthProfiles() ([]*domain.AuthProfile, error)
+	GetAuthProfilesDir() (string, error)
+	UpdateAuthProfile(profile *domain.AuthProfile) error
+	DeleteAuthProfile(profile *domain.AuthProfile) error
+	GetNewAuthProfileFilePath(name string) (*FilePath, error)
 }
 
 type FilePath struct {
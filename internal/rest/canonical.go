@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+)
+
+// CanonicalizeJSON re-emits s with sorted object keys, the same indentation as PrettyJSON
+// and numbers in a single form, so 1.0, 1e0 and 1 all become 1. Array order is kept.
+func CanonicalizeJSON(s string) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(s)))
+	decoder.UseNumber()
+
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return "", err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return "", errors.New("invalid json: unexpected data after the top-level value")
+	}
+
+	// encoding/json sorts map keys when marshaling, only the numbers need work
+	out, err := json.MarshalIndent(canonicalNumbers(data), "", "    ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func canonicalNumbers(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = canonicalNumbers(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = canonicalNumbers(item)
+		}
+		return v
+	case json.Number:
+		return canonicalNumber(v)
+	default:
+		return v
+	}
+}
+
+func canonicalNumber(n json.Number) json.Number {
+	// integers are kept exact, whatever their size
+	if i, ok := new(big.Int).SetString(n.String(), 10); ok {
+		return json.Number(i.String())
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return n
+	}
+
+	if f == float64(int64(f)) && f >= -(1<<53) && f <= 1<<53 {
+		return json.Number(strconv.FormatInt(int64(f), 10))
+	}
+
+	out, err := json.Marshal(f)
+	if err != nil {
+		return n
+	}
+	return json.Number(out)
+}
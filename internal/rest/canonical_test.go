@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"testing"
+)
+
+func TestCanonicalizeJSON(t *testing.T) {
+	a, err := CanonicalizeJSON(`{"b":{"y":2,"x":1.0},"a":[3,1,2],"c":1e2}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b, err := CanonicalizeJSON(`{ "c": 100, "a": [3, 1, 2], "b": {"x": 1, "y": 2.00} }`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if a != b {
+		t.Errorf("expected equal documents to canonicalize identically, got\n%s\nand\n%s", a, b)
+	}
+
+	want := "{\n    \"a\": [\n        3,\n        1,\n        2\n    ],\n    \"b\": {\n        \"x\": 1,\n        \"y\": 2\n    },\n    \"c\": 100\n}"
+	if a != want {
+		t.Errorf("CanonicalizeJSON() = %q, want %q", a, want)
+	}
+}
+
+func TestCanonicalizeJSON_Numbers(t *testing.T) {
+	tests := map[string]string{
+		"12345678901234567890": "12345678901234567890",
+		"-0":                   "0",
+		"0.5":                  "0.5",
+		"1.50":                 "1.5",
+		"2.5e-3":               "0.0025",
+	}
+
+	for in, want := range tests {
+		got, err := CanonicalizeJSON(in)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", in, err)
+		}
+
+		if got != want {
+			t.Errorf("CanonicalizeJSON(%s) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestCanonicalizeJSON_Invalid(t *testing.T) {
+	for _, s := range []string{`{"a":`, `{"a":1} {"b":2}`} {
+		if _, err := CanonicalizeJSON(s); err == nil {
+			t.Errorf("expected an error for %s", s)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+// authCommandTimeout bounds how long an auth command can run, it should only print a token.
+const authCommandTimeout = 30 * time.Second
+
+type commandOutput struct {
+	value   string
+	expires time.Time
+}
+
+func commandAuthHeader(auth *domain.CommandAuth) string {
+	if auth.Header == "" {
+		return "Authorization"
+	}
+	return auth.Header
+}
+
+// runAuthCommand returns the trimmed stdout of the auth command, reusing the last output
+// while it is younger than the ttl of the auth.
+//
+// The command is split into arguments and run without a shell, with the privileges of the
+// user running the app. Variables are never substituted into the command line, they are
+// passed to the command as CHAPAR_<name> environment variables instead.
+func (s *Service) runAuthCommand(ctx context.Context, auth *domain.CommandAuth, variables map[string]string) (string, error) {
+	argv, err := splitCommand(auth.Command)
+	if err != nil {
+		return "", err
+	}
+
+	if len(argv) == 0 {
+		return "", fmt.Errorf("auth command is empty")
+	}
+
+	env := commandEnv(variables)
+	cacheKey := auth.Command + "\x00" + strings.Join(env, "\x00")
+	if cached, ok := s.commandOutputs.Get(cacheKey); ok && auth.TTLSeconds > 0 && time.Now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, authCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Env = append(os.Environ(), env...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("auth command failed: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("auth command failed: %w", err)
+	}
+
+	value := strings.TrimSpace(stdout.String())
+	if auth.TTLSeconds > 0 {
+		s.commandOutputs.Set(cacheKey, commandOutput{
+			value:   value,
+			expires: time.Now().Add(time.Duration(auth.TTLSeconds) * time.Second),
+		})
+	}
+
+	return value, nil
+}
+
+// commandEnvPrefix is put before the variable names exported to auth commands, so a variable
+// named PATH or HOME can not change the environment the command runs in.
+const commandEnvPrefix = "CHAPAR_"
+
+// commandEnv returns the variables as sorted CHAPAR_KEY=value pairs. The dynamic variables are
+// left out as they change on every send and would defeat the output cache, and so are names that
+// can not be environment variable names.
+func commandEnv(variables map[string]string) []string {
+	dynamic := dynamicVariables()
+	out := make([]string, 0, len(variables))
+	for k, v := range variables {
+		if _, ok := dynamic[k]; ok || k == "" || strings.ContainsAny(k, "=\x00") {
+			continue
+		}
+		out = append(out, commandEnvPrefix+k+"="+v)
+	}
+
+	sort.Strings(out)
+	return out
+}
+
+// splitCommand splits a command line into arguments the way a shell does for words, honouring
+// single and double quotes and backslash escapes. Nothing else is interpreted: there is no
+// expansion, globbing, piping or command chaining.
+func splitCommand(command string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range command {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if escaped || quote != 0 {
+		return nil, fmt.Errorf("auth command has an unterminated quote or escape")
+	}
+
+	if inWord {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
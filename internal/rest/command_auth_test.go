@@ -0,0 +1,168 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+func TestService_SendRequest_CommandAuth(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub command uses sh")
+	}
+
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("command")
+	req.Spec.HTTP.URL = server.URL
+	req.Spec.HTTP.Request.Auth = domain.Auth{
+		Type:        domain.AuthTypeCommand,
+		CommandAuth: &domain.CommandAuth{Command: `sh -c 'printf %s "$CHAPAR_token"'`, Prefix: "Bearer "},
+	}
+	req.Spec.HTTP.Request.Variables = []domain.KeyValue{{Key: "token", Value: "stub-token", Enable: true}}
+	requests.AddRequest(req)
+
+	service := New(requests, state.NewEnvironments(nil), state.NewAuthProfiles(nil), nil)
+	if _, err := service.SendRequest(req.MetaData.ID, ""); err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if received != "Bearer stub-token" {
+		t.Errorf("expected Authorization Bearer stub-token, got %q", received)
+	}
+}
+
+func TestService_runAuthCommand_NoInjection(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub command uses sh")
+	}
+
+	marker := filepath.Join(t.TempDir(), "injected")
+	variables := map[string]string{"token": "x; touch " + marker}
+	service := New(state.NewRequests(nil), state.NewEnvironments(nil), state.NewAuthProfiles(nil), nil)
+
+	// variables are not substituted into the command line
+	out, err := service.runAuthCommand(context.Background(), &domain.CommandAuth{Command: "echo {{token}}"}, variables)
+	if err != nil {
+		t.Fatalf("failed to run command: %v", err)
+	}
+
+	if out != "{{token}}" {
+		t.Errorf("expected the command line to be left as is, got %q", out)
+	}
+
+	// and reach the command as data through its environment
+	out, err = service.runAuthCommand(context.Background(), &domain.CommandAuth{Command: `sh -c 'printf %s "$CHAPAR_token"'`}, variables)
+	if err != nil {
+		t.Fatalf("failed to run command: %v", err)
+	}
+
+	if out != variables["token"] {
+		t.Errorf("expected output %q, got %q", variables["token"], out)
+	}
+
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("expected the variable value not to run as shell code")
+	}
+}
+
+func TestService_runAuthCommand_PrefixedEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub command uses sh")
+	}
+
+	// a variable named PATH must not change where the command is looked up
+	variables := map[string]string{"PATH": "/nonexistent"}
+	service := New(state.NewRequests(nil), state.NewEnvironments(nil), state.NewAuthProfiles(nil), nil)
+
+	out, err := service.runAuthCommand(context.Background(), &domain.CommandAuth{Command: `sh -c 'printf %s "$CHAPAR_PATH"'`}, variables)
+	if err != nil {
+		t.Fatalf("failed to run command: %v", err)
+	}
+
+	if out != "/nonexistent" {
+		t.Errorf("expected the variable as CHAPAR_PATH, got %q", out)
+	}
+}
+
+func TestService_runAuthCommand_TTL(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub command uses sh")
+	}
+
+	variables := map[string]string{"counter": filepath.Join(t.TempDir(), "runs")}
+	auth := &domain.CommandAuth{
+		Command:    `sh -c 'echo run >> "$CHAPAR_counter" && echo token'`,
+		TTLSeconds: 60,
+	}
+
+	service := New(state.NewRequests(nil), state.NewEnvironments(nil), state.NewAuthProfiles(nil), nil)
+	for i := 0; i < 2; i++ {
+		out, err := service.runAuthCommand(context.Background(), auth, variables)
+		if err != nil {
+			t.Fatalf("failed to run command: %v", err)
+		}
+
+		if out != "token" {
+			t.Errorf("expected output token, got %q", out)
+		}
+	}
+
+	data, err := os.ReadFile(variables["counter"])
+	if err != nil {
+		t.Fatalf("failed to read counter: %v", err)
+	}
+
+	if runs := strings.Count(string(data), "run"); runs != 1 {
+		t.Errorf("expected the command to run once within the ttl, it ran %d times", runs)
+	}
+}
+
+func TestService_runAuthCommand_Failure(t *testing.T) {
+	service := New(state.NewRequests(nil), state.NewEnvironments(nil), state.NewAuthProfiles(nil), nil)
+	for _, command := range []string{"chapar-missing-auth-command", "", "print 'unterminated"} {
+		if _, err := service.runAuthCommand(context.Background(), &domain.CommandAuth{Command: command}, nil); err == nil {
+			t.Errorf("expected an error for command %q", command)
+		}
+	}
+}
+
+func Test_splitCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{command: "gcloud auth print-access-token", want: []string{"gcloud", "auth", "print-access-token"}},
+		{command: `  vault  read -field=token "secret/my app" `, want: []string{"vault", "read", "-field=token", "secret/my app"}},
+		{command: `sh -c 'echo "$TOKEN"; exit'`, want: []string{"sh", "-c", `echo "$TOKEN"; exit`}},
+		{command: `a\ b "c\"d" ''`, want: []string{"a b", `c"d`, ""}},
+		{command: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		got, err := splitCommand(tt.command)
+		if err != nil {
+			t.Errorf("splitCommand(%q) returned error %v", tt.command, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommand(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"strings"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+const redacted = "<redacted>"
+
+// CurlHeaders formats the headers as curl -H arguments, one per line, with the values of
+// credential headers redacted so the output can be shared.
+func CurlHeaders(kvs []domain.KeyValue) string {
+	lines := make([]string, 0, len(kvs))
+	for _, kv := range kvs {
+		if kv.Key == "" {
+			continue
+		}
+
+		header := kv.Key + ": " + redactHeaderValue(kv.Key, kv.Value)
+		lines = append(lines, "-H '"+strings.ReplaceAll(header, "'", `'\''`)+"'")
+	}
+
+	return strings.Join(lines, " \\\n")
+}
+
+func redactHeaderValue(key, value string) string {
+	name := strings.ToLower(key)
+	switch {
+	case name == "authorization" || name == "proxy-authorization":
+		// keep the scheme, it tells how to get a new credential
+		if scheme, _, ok := strings.Cut(value, " "); ok {
+			return scheme + " " + redacted
+		}
+		return redacted
+	case name == "cookie" || name == "set-cookie",
+		strings.Contains(name, "api-key"),
+		strings.Contains(name, "token"),
+		strings.Contains(name, "secret"),
+		strings.Contains(name, "password"):
+		return redacted
+	}
+
+	return value
+}
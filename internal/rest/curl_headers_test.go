@@ -0,0 +1,34 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+func TestCurlHeaders(t *testing.T) {
+	headers := []domain.KeyValue{
+		{Key: "Content-Type", Value: "application/json"},
+		{Key: "Authorization", Value: "Bearer secret-token"},
+		{Key: "Proxy-Authorization", Value: "opaque"},
+		{Key: "X-Api-Key", Value: "12345"},
+		{Key: "Set-Cookie", Value: "session=abc"},
+		{Key: "X-Quote", Value: "it's"},
+		{Key: "", Value: "skipped"},
+	}
+
+	want := `-H 'Content-Type: application/json' \
+-H 'Authorization: Bearer <redacted>' \
+-H 'Proxy-Authorization: <redacted>' \
+-H 'X-Api-Key: <redacted>' \
+-H 'Set-Cookie: <redacted>' \
+-H 'X-Quote: it'\''s'`
+
+	if got := CurlHeaders(headers); got != want {
+		t.Errorf("unexpected curl headers:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := CurlHeaders(nil); got != "" {
+		t.Errorf("expected no output for no headers, got %q", got)
+	}
+}
@@ -0,0 +1,120 @@
+package rest
+
+import (
+	"strings"
+)
+
+type DiffKind int
+
+const (
+	DiffEqual DiffKind = iota
+	DiffAdded
+	DiffRemoved
+)
+
+type DiffLine struct {
+	Kind DiffKind
+	Text string
+}
+
+// maxDiffCells bounds the memory used to diff the changed middle part of two bodies,
+// past it the whole middle part is reported as removed and added.
+const maxDiffCells = 4_000_000
+
+// Diff compares two response bodies line by line, a being the previous response and b the current one.
+// JSON bodies are canonicalized first so key order, whitespace and number formatting do not show up as changes.
+func Diff(a, b string) []DiffLine {
+	aLines := strings.Split(normalizeForDiff(a), "\n")
+	bLines := strings.Split(normalizeForDiff(b), "\n")
+
+	// the common prefix and suffix are kept out of the lcs table
+	prefix := 0
+	for prefix < len(aLines) && prefix < len(bLines) && aLines[prefix] == bLines[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(aLines)-prefix && suffix < len(bLines)-prefix &&
+		aLines[len(aLines)-1-suffix] == bLines[len(bLines)-1-suffix] {
+		suffix++
+	}
+
+	out := make([]DiffLine, 0, len(aLines)+len(bLines))
+	for _, l := range aLines[:prefix] {
+		out = append(out, DiffLine{Kind: DiffEqual, Text: l})
+	}
+
+	out = append(out, diffLines(aLines[prefix:len(aLines)-suffix], bLines[prefix:len(bLines)-suffix])...)
+
+	for _, l := range aLines[len(aLines)-suffix:] {
+		out = append(out, DiffLine{Kind: DiffEqual, Text: l})
+	}
+
+	return out
+}
+
+// diffLines walks the longest common subsequence of a and b.
+func diffLines(a, b []string) []DiffLine {
+	out := make([]DiffLine, 0, len(a)+len(b))
+
+	if len(a)*len(b) > maxDiffCells {
+		for _, l := range a {
+			out = append(out, DiffLine{Kind: DiffRemoved, Text: l})
+		}
+		for _, l := range b {
+			out = append(out, DiffLine{Kind: DiffAdded, Text: l})
+		}
+		return out
+	}
+
+	// lcs[i][j] is the length of the lcs of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, DiffLine{Kind: DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, DiffLine{Kind: DiffRemoved, Text: a[i]})
+			i++
+		default:
+			out = append(out, DiffLine{Kind: DiffAdded, Text: b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		out = append(out, DiffLine{Kind: DiffRemoved, Text: a[i]})
+	}
+
+	for ; j < len(b); j++ {
+		out = append(out, DiffLine{Kind: DiffAdded, Text: b[j]})
+	}
+
+	return out
+}
+
+// normalizeForDiff canonicalizes JSON, anything else is returned as is.
+func normalizeForDiff(s string) string {
+	out, err := CanonicalizeJSON(s)
+	if err != nil {
+		return s
+	}
+	return out
+}
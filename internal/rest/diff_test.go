@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff_FieldChange(t *testing.T) {
+	previous := `{"name":"chapar","status":"draft","id":1}`
+	// same document with a changed field and a different key order
+	current := `{"id":1,"status":"published","name":"chapar"}`
+
+	want := []DiffLine{
+		{Kind: DiffEqual, Text: "{"},
+		{Kind: DiffEqual, Text: `    "id": 1,`},
+		{Kind: DiffEqual, Text: `    "name": "chapar",`},
+		{Kind: DiffRemoved, Text: `    "status": "draft"`},
+		{Kind: DiffAdded, Text: `    "status": "published"`},
+		{Kind: DiffEqual, Text: "}"},
+	}
+
+	if got := Diff(previous, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+}
+
+func TestDiff_Text(t *testing.T) {
+	got := Diff("a\nb\nc", "a\nc\nd")
+	want := []DiffLine{
+		{Kind: DiffEqual, Text: "a"},
+		{Kind: DiffRemoved, Text: "b"},
+		{Kind: DiffEqual, Text: "c"},
+		{Kind: DiffAdded, Text: "d"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+}
+
+func TestDiff_Equal(t *testing.T) {
+	for _, l := range Diff(`{"a":1,"b":2}`, `{"b":2,"a":1}`) {
+		if l.Kind != DiffEqual {
+			t.Errorf("expected no changes, got %v", l)
+		}
+	}
+}
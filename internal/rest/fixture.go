@@ -0,0 +1,119 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"gopkg.in/yaml.v2"
+)
+
+// Fixture captures a request as it was sent, with variables and auth applied, and the response it got.
+// It is meant to be replayed by tests. The values of credential headers are redacted, so fixtures
+// can be committed along with the tests.
+type Fixture struct {
+	Request  FixtureRequest  `yaml:"request"`
+	Response FixtureResponse `yaml:"response"`
+}
+
+type FixtureRequest struct {
+	Method  string            `yaml:"method"`
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Body    string            `yaml:"body"`
+}
+
+type FixtureResponse struct {
+	StatusCode int               `yaml:"statusCode"`
+	Headers    map[string]string `yaml:"headers"`
+	Body       string            `yaml:"body"`
+}
+
+// newFixtureRequest captures req right before it is sent, the body is put back so it can still be sent.
+// Credential headers are redacted, along with secretHeaders which are set by the auth of the request.
+func newFixtureRequest(req *http.Request, secretHeaders ...string) (*FixtureRequest, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FixtureRequest{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: fixtureHeaders(req.Header, secretHeaders),
+		Body:    string(body),
+	}, nil
+}
+
+func fixtureHeaders(header http.Header, secretHeaders []string) map[string]string {
+	headers := make(map[string]string, len(header))
+	for k, v := range header {
+		value := strings.Join(v, ", ")
+		if slices.ContainsFunc(secretHeaders, func(name string) bool { return strings.EqualFold(name, k) }) {
+			value = redacted
+		}
+		headers[k] = redactHeaderValue(k, value)
+	}
+	return headers
+}
+
+// authHeaderNames returns the headers the auth of the request sets under a name chosen by the user.
+func authHeaderNames(auth domain.Auth) []string {
+	names := make([]string, 0, 2)
+	if auth.APIKeyAuth != nil && auth.APIKeyAuth.Key != "" {
+		names = append(names, auth.APIKeyAuth.Key)
+	}
+	if auth.Type == domain.AuthTypeCommand && auth.CommandAuth != nil {
+		names = append(names, commandAuthHeader(auth.CommandAuth))
+	}
+	return names
+}
+
+// Fixture returns the fixture of the response, nil if the sent request was not captured.
+func (r *Response) Fixture() *Fixture {
+	if r.SentRequest == nil {
+		return nil
+	}
+
+	return &Fixture{
+		Request: *r.SentRequest,
+		Response: FixtureResponse{
+			StatusCode: r.StatusCode,
+			Headers:    redactHeaders(r.Headers),
+			Body:       string(r.Body),
+		},
+	}
+}
+
+func redactHeaders(headers map[string]string) map[string]string {
+	out := make(map[string]string, len(headers))
+	for k, v := range headers {
+		out[k] = redactHeaderValue(k, v)
+	}
+	return out
+}
+
+func WriteFixture(w io.Writer, fixture *Fixture) error {
+	data, err := yaml.Marshal(fixture)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+func ReadFixture(r io.Reader) (*Fixture, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &Fixture{}
+	if err := yaml.Unmarshal(data, fixture); err != nil {
+		return nil, err
+	}
+	return fixture, nil
+}
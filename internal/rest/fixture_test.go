@@ -0,0 +1,128 @@
+package rest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+func TestFixture_WriteRead(t *testing.T) {
+	var receivedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		receivedBody = string(data)
+		w.Header().Set("X-Request-Id", "42")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":42}`))
+	}))
+	defer server.Close()
+
+	environments := state.NewEnvironments(nil)
+	env := domain.NewEnvironment("dev")
+	env.Spec.Values = []domain.KeyValue{{Key: "name", Value: "chapar"}}
+	environments.AddEnvironment(env, state.SourceController)
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("create")
+	req.Spec.HTTP.Method = http.MethodPost
+	req.Spec.HTTP.URL = server.URL + "/users"
+	req.Spec.HTTP.Request.Headers = []domain.KeyValue{{Key: "X-Name", Value: "{{name}}", Enable: true}}
+	req.Spec.HTTP.Request.Body = domain.Body{Type: domain.BodyTypeJSON, Data: `{"name":"{{name}}"}`}
+	requests.AddRequest(req)
+
+	service := New(requests, environments, state.NewAuthProfiles(nil), nil)
+	res, err := service.SendRequest(req.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if receivedBody != `{"name":"chapar"}` {
+		t.Errorf("expected the body to still be sent, got %q", receivedBody)
+	}
+
+	fixture := res.Fixture()
+	if fixture == nil {
+		t.Fatalf("expected a fixture")
+	}
+
+	var buf bytes.Buffer
+	if err := WriteFixture(&buf, fixture); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	got, err := ReadFixture(&buf)
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, fixture) {
+		t.Errorf("expected %+v after a round trip, got %+v", fixture, got)
+	}
+
+	if got.Request.Method != http.MethodPost || got.Request.URL != server.URL+"/users" {
+		t.Errorf("unexpected request %s %s", got.Request.Method, got.Request.URL)
+	}
+
+	if got.Request.Headers["X-Name"] != "chapar" || got.Request.Body != `{"name":"chapar"}` {
+		t.Errorf("expected the resolved request to be captured, got %+v", got.Request)
+	}
+
+	if got.Response.StatusCode != http.StatusCreated || got.Response.Body != `{"id":42}` || got.Response.Headers["X-Request-Id"] != "42" {
+		t.Errorf("unexpected response %+v", got.Response)
+	}
+}
+
+func TestFixture_RedactsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Header().Set("X-Request-Id", "42")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("secret")
+	req.Spec.HTTP.URL = server.URL
+	req.Spec.HTTP.Request.Headers = []domain.KeyValue{
+		{Key: "Cookie", Value: "session=abc", Enable: true},
+		{Key: "X-Name", Value: "chapar", Enable: true},
+	}
+	req.Spec.HTTP.Request.Auth = domain.Auth{
+		Type:       domain.AuthTypeAPIKey,
+		APIKeyAuth: &domain.APIKeyAuth{Key: "X-Key", Value: "key-value"},
+		TokenAuth:  &domain.TokenAuth{Token: "token-value"},
+	}
+	requests.AddRequest(req)
+
+	service := New(requests, state.NewEnvironments(nil), state.NewAuthProfiles(nil), nil)
+	res, err := service.SendRequest(req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteFixture(&buf, res.Fixture()); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	for _, secret := range []string{"session=abc", "key-value", "token-value"} {
+		if bytes.Contains(buf.Bytes(), []byte(secret)) {
+			t.Errorf("expected %q to be redacted from the fixture:\n%s", secret, buf.String())
+		}
+	}
+
+	fixture := res.Fixture()
+	if fixture.Request.Headers["Authorization"] != "Bearer "+redacted {
+		t.Errorf("expected the auth scheme to be kept, got %q", fixture.Request.Headers["Authorization"])
+	}
+
+	if fixture.Request.Headers["X-Name"] != "chapar" || fixture.Response.Headers["X-Request-Id"] != "42" {
+		t.Errorf("expected other headers to be kept, got %+v and %+v", fixture.Request.Headers, fixture.Response.Headers)
+	}
+}
@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+// ExportAsGoCode returns a Go program sending the request with net/http, with the variables of the
+// environment applied. Auth that is computed at send time, such as JWT or AWS signatures, and
+// file bodies are left as comments to fill in.
+func (s *Service) ExportAsGoCode(requestID, activeEnvironmentID string) (string, error) {
+	spec, err := s.resolve(requestID, activeEnvironmentID)
+	if err != nil {
+		return "", err
+	}
+
+	return goCode(spec), nil
+}
+
+// resolve returns a copy of the request spec with the auth profile and the variables of the environment applied,
+// as it would be sent.
+func (s *Service) resolve(requestID, activeEnvironmentID string) (*domain.HTTPRequestSpec, error) {
+	req := s.requests.GetRequest(requestID)
+	if req == nil {
+		return nil, fmt.Errorf("request with id %s not found", requestID)
+	}
+
+	r := req.Clone()
+	activeEnvironmentID = s.environmentID(r, activeEnvironmentID)
+
+	var envSpec *domain.EnvSpec
+	if activeEnvironmentID != "" {
+		env := s.environments.GetEnvironment(activeEnvironmentID)
+		if env == nil {
+			return nil, fmt.Errorf("environment with id %s not found", activeEnvironmentID)
+		}
+		envSpec = &env.Clone().Spec
+		applyDefaultAuth(r.Spec.HTTP.Request, env)
+	}
+
+	if err := s.resolveAuthProfile(r.Spec.HTTP.Request); err != nil {
+		return nil, err
+	}
+
+	return applyVariables(r.Spec.HTTP, envSpec), nil
+}
+
+func goCode(spec *domain.HTTPRequestSpec) string {
+	method := spec.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	address := spec.URL
+	var lines []string
+	body := ""
+
+	if req := spec.Request; req != nil {
+		for _, p := range req.PathParams {
+			address = strings.ReplaceAll(address, "{"+p.Key+"}", p.Value)
+		}
+
+		switch req.Body.Type {
+		case domain.BodyTypeJSON, domain.BodyTypeXML, domain.BodyTypeText:
+			body = req.Body.Data
+		case domain.BodyTypeUrlencoded:
+			form := url.Values{}
+			for _, f := range req.Body.URLEncoded {
+				form.Add(f.Key, f.Value)
+			}
+			body = form.Encode()
+			lines = append(lines, header("Content-Type", "application/x-www-form-urlencoded"))
+		case domain.BodyTypeFormData, domain.BodyTypeBinary:
+			lines = append(lines, "\t// the "+req.Body.Type+" body is not exported, set req.Body to send it")
+		}
+
+		for _, h := range req.Headers {
+			if h.Enable {
+				lines = append(lines, header(h.Key, h.Value))
+			}
+		}
+
+		lines = append(lines, goAuth(req.Auth)...)
+	}
+
+	imports := []string{`"fmt"`, `"io"`, `"net/http"`}
+	bodyArg := "nil"
+	if body != "" {
+		imports = append(imports, `"strings"`)
+		bodyArg = "strings.NewReader(" + strconv.Quote(body) + ")"
+	}
+
+	var b strings.Builder
+	b.WriteString("package main\n\nimport (\n")
+	for _, imp := range imports {
+		b.WriteString("\t" + imp + "\n")
+	}
+	b.WriteString(")\n\nfunc main() {\n")
+	fmt.Fprintf(&b, "\treq, err := http.NewRequest(%s, %s, %s)\n", strconv.Quote(method), strconv.Quote(address), bodyArg)
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n\n")
+	if len(lines) > 0 {
+		b.WriteString(strings.Join(lines, "\n") + "\n\n")
+	}
+	b.WriteString("\tres, err := http.DefaultClient.Do(req)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tdefer res.Body.Close()\n\n")
+	b.WriteString("\tdata, err := io.ReadAll(res.Body)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n\n")
+	b.WriteString("\tfmt.Println(res.Status)\n")
+	b.WriteString("\tfmt.Println(string(data))\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func header(key, value string) string {
+	return "\treq.Header.Add(" + strconv.Quote(key) + ", " + strconv.Quote(value) + ")"
+}
+
+func goAuth(auth domain.Auth) []string {
+	switch {
+	case auth.Type == domain.AuthTypeToken && auth.TokenAuth != nil:
+		return []string{header("Authorization", "Bearer "+auth.TokenAuth.Token)}
+	case auth.Type == domain.AuthTypeBasic && auth.BasicAuth != nil:
+		return []string{"\treq.SetBasicAuth(" + strconv.Quote(auth.BasicAuth.Username) + ", " + strconv.Quote(auth.BasicAuth.Password) + ")"}
+	case auth.Type == domain.AuthTypeAPIKey && auth.APIKeyAuth != nil:
+		return []string{header(auth.APIKeyAuth.Key, auth.APIKeyAuth.Value)}
+	case auth.Type == domain.AuthTypeJWT, auth.Type == domain.AuthTypeAWSSigV4, auth.Type == domain.AuthTypeCommand:
+		return []string{"\t// the " + auth.Type + " auth is computed at send time and is not exported"}
+	}
+	return nil
+}
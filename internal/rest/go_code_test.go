@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+const goCodeGolden = `package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+func main() {
+	req, err := http.NewRequest("POST", "https://api.example.com/users/42", strings.NewReader("{\"name\":\"chapar\"}"))
+	if err != nil {
+		panic(err)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", "Bearer secret-token")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		panic(err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(res.Status)
+	fmt.Println(string(data))
+}
+`
+
+func TestService_ExportAsGoCode(t *testing.T) {
+	env := domain.NewEnvironment("dev")
+	env.Spec.Values = []domain.KeyValue{
+		{Key: "host", Value: "https://api.example.com", Enable: true},
+		{Key: "token", Value: "secret-token", Enable: true},
+	}
+	environments := state.NewEnvironments(nil)
+	environments.AddEnvironment(env, state.SourceController)
+
+	req := domain.NewRequest("create user")
+	req.Spec.HTTP.Method = "POST"
+	req.Spec.HTTP.URL = "{{host}}/users/{id}"
+	req.Spec.HTTP.Request.PathParams = []domain.KeyValue{{Key: "id", Value: "42", Enable: true}}
+	req.Spec.HTTP.Request.Headers = []domain.KeyValue{
+		{Key: "Content-Type", Value: "application/json", Enable: true},
+		{Key: "X-Disabled", Value: "ignored", Enable: false},
+	}
+	req.Spec.HTTP.Request.Body = domain.Body{Type: domain.BodyTypeJSON, Data: `{"name":"chapar"}`}
+	req.Spec.HTTP.Request.Auth = domain.Auth{Type: domain.AuthTypeToken, TokenAuth: &domain.TokenAuth{Token: "{{token}}"}}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	service := New(requests, environments, state.NewAuthProfiles(nil), nil)
+	code, err := service.ExportAsGoCode(req.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	if code != goCodeGolden {
+		t.Errorf("unexpected code:\n%s\nwant:\n%s", code, goCodeGolden)
+	}
+
+	if _, err := format.Source([]byte(code)); err != nil {
+		t.Errorf("exported code is not valid go: %v", err)
+	}
+}
@@ -2,10 +2,13 @@ package rest
 
 import (
 	"encoding/json"
+	"errors"
 
 	"github.com/PaesslerAG/jsonpath"
 )
 
+var ErrJSONPathNoMatch = errors.New("no match found")
+
 func GetJSONPATH(input string, path string) (interface{}, error) {
 	v := interface{}(nil)
 	if err := json.Unmarshal([]byte(input), &v); err != nil {
@@ -23,3 +26,30 @@ func GetJSONPATH(input string, path string) (interface{}, error) {
 
 	return pathData, nil
 }
+
+// ExtractJSONPath returns the value matched by the JSONPath expression in the given JSON body.
+// Strings are returned as is, any other value (objects, arrays, numbers) is returned as indented JSON.
+func ExtractJSONPath(body, expr string) (string, error) {
+	data, err := GetJSONPATH(body, expr)
+	if err != nil {
+		return "", err
+	}
+
+	switch v := data.(type) {
+	case nil:
+		return "", ErrJSONPathNoMatch
+	case string:
+		return v, nil
+	case []interface{}:
+		if len(v) == 0 {
+			return "", ErrJSONPathNoMatch
+		}
+	}
+
+	out, err := json.MarshalIndent(data, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
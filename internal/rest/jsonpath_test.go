@@ -0,0 +1,48 @@
+package rest
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExtractJSONPath(t *testing.T) {
+	body := `{"user": {"name": "john", "age": 30}, "items": [{"id": 1}, {"id": 2}], "empty": []}`
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    string
+		wantErr error
+	}{
+		{name: "string value", expr: "$.user.name", want: "john"},
+		{name: "number value", expr: "$.user.age", want: "30"},
+		{name: "object value", expr: "$.user", want: "{\n    \"age\": 30,\n    \"name\": \"john\"\n}"},
+		{name: "array values", expr: "$.items[*].id", want: "[\n    1,\n    2\n]"},
+		{name: "array index", expr: "$.items[1].id", want: "2"},
+		{name: "empty match", expr: "$.empty[*]", wantErr: ErrJSONPathNoMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractJSONPath(body, tt.expr)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := ExtractJSONPath(body, "$.missing"); err == nil {
+		t.Errorf("expected error for missing key")
+	}
+}
@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+var ErrInvalidPrivateKey = errors.New("invalid private key, expected a PEM encoded RSA key")
+
+// SignJWT builds a token from the claims of auth and signs it with the configured algorithm.
+// When TTLSeconds is set, iat and exp are computed from now.
+func SignJWT(auth *domain.JWTAuth, now time.Time) (string, error) {
+	claims := make(map[string]any)
+	if strings.TrimSpace(auth.Claims) != "" {
+		if err := json.Unmarshal([]byte(auth.Claims), &claims); err != nil {
+			return "", fmt.Errorf("invalid jwt claims: %w", err)
+		}
+	}
+
+	if auth.TTLSeconds > 0 {
+		claims["iat"] = now.Unix()
+		claims["exp"] = now.Add(time.Duration(auth.TTLSeconds) * time.Second).Unix()
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": auth.Algorithm, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hashed := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch auth.Algorithm {
+	case domain.JWTAlgorithmHS256:
+		mac := hmac.New(sha256.New, []byte(auth.Secret))
+		mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	case domain.JWTAlgorithmRS256:
+		key, err := parseRSAPrivateKey(auth.Secret)
+		if err != nil {
+			return "", err
+		}
+
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("unsupported jwt algorithm %q", auth.Algorithm)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parseRSAPrivateKey accepts both PKCS#1 and PKCS#8 PEM blocks.
+func parseRSAPrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidPrivateKey
+	}
+	return rsaKey, nil
+}
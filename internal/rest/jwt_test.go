@@ -0,0 +1,149 @@
+package rest
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+func decodeJWT(t *testing.T, token string) (header, claims map[string]any, signingInput string, signature []byte) {
+	t.Helper()
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 token parts, got %d", len(parts))
+	}
+
+	decode := func(part string, out any) {
+		data, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			t.Fatalf("failed to decode token part: %v", err)
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("failed to unmarshal token part: %v", err)
+		}
+	}
+
+	decode(parts[0], &header)
+	decode(parts[1], &claims)
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("failed to decode signature: %v", err)
+	}
+
+	return header, claims, parts[0] + "." + parts[1], signature
+}
+
+func TestSignJWT_HS256(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	auth := &domain.JWTAuth{
+		Algorithm:  domain.JWTAlgorithmHS256,
+		Secret:     "secret",
+		Claims:     `{"sub":"user-1","role":"admin"}`,
+		TTLSeconds: 60,
+	}
+
+	token, err := SignJWT(auth, now)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	header, claims, signingInput, signature := decodeJWT(t, token)
+	if header["alg"] != "HS256" {
+		t.Errorf("expected alg HS256, got %v", header["alg"])
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(signingInput))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		t.Errorf("signature does not validate with the configured secret")
+	}
+
+	if claims["sub"] != "user-1" || claims["role"] != "admin" {
+		t.Errorf("unexpected claims %v", claims)
+	}
+
+	if claims["exp"] != float64(now.Unix()+60) {
+		t.Errorf("expected exp %d, got %v", now.Unix()+60, claims["exp"])
+	}
+}
+
+func TestSignJWT_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	auth := &domain.JWTAuth{
+		Algorithm: domain.JWTAlgorithmRS256,
+		Secret:    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		Claims:    `{"sub":"user-1"}`,
+	}
+
+	token, err := SignJWT(auth, time.Now())
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	_, claims, signingInput, signature := decodeJWT(t, token)
+	hashed := sha256.Sum256([]byte(signingInput))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hashed[:], signature); err != nil {
+		t.Errorf("signature does not validate with the public key: %v", err)
+	}
+
+	if _, ok := claims["exp"]; ok {
+		t.Errorf("expected no exp claim without a ttl")
+	}
+}
+
+func TestSignJWT_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		auth *domain.JWTAuth
+	}{
+		{name: "unsupported algorithm", auth: &domain.JWTAuth{Algorithm: "none"}},
+		{name: "invalid claims", auth: &domain.JWTAuth{Algorithm: domain.JWTAlgorithmHS256, Claims: "{"}},
+		{name: "invalid private key", auth: &domain.JWTAuth{Algorithm: domain.JWTAlgorithmRS256, Secret: "not a key"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SignJWT(tt.auth, time.Now()); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestSignJWT_NewJWTAuth(t *testing.T) {
+	// a fresh JWT auth only needs a secret to be signed
+	auth := domain.NewJWTAuth()
+	auth.Secret = "secret"
+
+	token, err := SignJWT(auth, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	header, _, _, _ := decodeJWT(t, token)
+	if header["alg"] != domain.JWTAlgorithmHS256 {
+		t.Errorf("expected alg HS256, got %v", header["alg"])
+	}
+}
@@ -0,0 +1,31 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// SendRequestMatrix sends the request once against each of the environments, one after the other,
+// and returns the responses by environment id. Variables and auth are resolved per environment and
+// the environment override of the request is ignored. Snapshots are not checked.
+// Environments that failed have no response and their errors are joined in the returned error.
+func (s *Service) SendRequestMatrix(requestID string, environmentIDs []string) (map[string]*Response, error) {
+	req := s.requests.GetRequest(requestID)
+	if req == nil {
+		return nil, fmt.Errorf("request with id %s not found", requestID)
+	}
+
+	responses := make(map[string]*Response, len(environmentIDs))
+	var errs []error
+	for _, envID := range environmentIDs {
+		res, err := s.send(context.Background(), req, envID, false)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("environment %s: %w", envID, err))
+			continue
+		}
+		responses[envID] = res
+	}
+
+	return responses, errors.Join(errs...)
+}
@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+func TestService_SendRequestMatrix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Query().Get("stage")))
+	}))
+	defer server.Close()
+
+	environments := state.NewEnvironments(nil)
+	ids := make([]string, 0)
+	for _, stage := range []string{"dev", "staging"} {
+		env := domain.NewEnvironment(stage)
+		env.Spec.Values = []domain.KeyValue{{Key: "stage", Value: stage, Enable: true}}
+		environments.AddEnvironment(env, state.SourceController)
+		ids = append(ids, env.MetaData.ID)
+	}
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("matrix")
+	req.Spec.HTTP.URL = server.URL + "?stage={{stage}}"
+	// the override is ignored by matrix runs
+	req.Spec.HTTP.EnvironmentOverride = ids[0]
+	requests.AddRequest(req)
+
+	service := New(requests, environments, state.NewAuthProfiles(nil), nil)
+	responses, err := service.SendRequestMatrix(req.MetaData.ID, append(ids, "missing"))
+	if err == nil {
+		t.Errorf("expected an error for the missing environment")
+	}
+
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(responses))
+	}
+
+	for i, stage := range []string{"dev", "staging"} {
+		if got := string(responses[ids[i]].Body); got != stage {
+			t.Errorf("expected %s response body %s, got %s", stage, stage, got)
+		}
+	}
+}
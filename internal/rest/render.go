@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dustin/go-humanize"
+)
+
+// TruncateForRender cuts body down to at most maxBytes for display, it returns the body as is
+// when maxBytes is zero or the body fits. The cut happens at the end of a line when there is one,
+// so pretty printed JSON is cut between values, and a note saying how much was shown is appended.
+func TruncateForRender(body string, maxBytes int) (string, bool) {
+	if maxBytes <= 0 || len(body) <= maxBytes {
+		return body, false
+	}
+
+	cut := body[:maxBytes]
+	if i := strings.LastIndexByte(cut, '\n'); i > 0 {
+		cut = cut[:i]
+	}
+
+	cut = trimPartialRune(cut)
+
+	note := fmt.Sprintf("\n... response truncated, showing %s of %s", humanize.Bytes(uint64(len(cut))), humanize.Bytes(uint64(len(body))))
+	return cut + note, true
+}
+
+// trimPartialRune drops a multi byte character split at the end of s. Invalid bytes elsewhere
+// are left alone, so binary bodies keep their preview.
+func trimPartialRune(s string) string {
+	for i := len(s) - 1; i >= 0 && i >= len(s)-(utf8.UTFMax-1); i-- {
+		if !utf8.RuneStart(s[i]) {
+			continue
+		}
+
+		if !utf8.FullRuneInString(s[i:]) {
+			return s[:i]
+		}
+		return s
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+package rest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTruncateForRender(t *testing.T) {
+	body := "{\n    \"a\": 1,\n    \"b\": \"héllo\",\n    \"c\": 3\n}"
+
+	got, truncated := TruncateForRender(body, 0)
+	if truncated || got != body {
+		t.Errorf("expected no truncation without a limit")
+	}
+
+	got, truncated = TruncateForRender(body, len(body))
+	if truncated || got != body {
+		t.Errorf("expected no truncation when the body fits")
+	}
+
+	got, truncated = TruncateForRender(body, 20)
+	if !truncated {
+		t.Fatalf("expected the body to be truncated")
+	}
+
+	shown, note, ok := strings.Cut(got, "\n... response truncated")
+	if !ok {
+		t.Fatalf("expected a note marking the cut, got %q", got)
+	}
+
+	if shown != "{\n    \"a\": 1," {
+		t.Errorf("expected the cut at the last line break, got %q", shown)
+	}
+
+	if !strings.HasSuffix(note, "of 45 B") {
+		t.Errorf("expected the note to report the full size, got %q", note)
+	}
+}
+
+func TestTruncateForRender_NoLineBreak(t *testing.T) {
+	body := strings.Repeat("é", 10)
+
+	got, truncated := TruncateForRender(body, 5)
+	if !truncated {
+		t.Fatalf("expected the body to be truncated")
+	}
+
+	shown, _, _ := strings.Cut(got, "\n...")
+	if shown != "éé" {
+		t.Errorf("expected the cut not to split a character, got %q", shown)
+	}
+}
+
+func TestTruncateForRender_InvalidBytes(t *testing.T) {
+	// latin-1 and binary bytes are not valid UTF-8 but must not shrink the preview
+	body := "caf\xe9 \xff\x00\x80 data " + strings.Repeat("x", 100)
+
+	got, truncated := TruncateForRender(body, 50)
+	if !truncated {
+		t.Fatalf("expected the body to be truncated")
+	}
+
+	shown, _, _ := strings.Cut(got, "\n...")
+	if shown != body[:50] {
+		t.Errorf("expected the first 50 bytes to be shown, got %q", shown)
+	}
+
+	// a character split at the end is still dropped
+	body = "\xff" + strings.Repeat("é", 10)
+	got, _ = TruncateForRender(body, 4)
+	shown, _, _ = strings.Cut(got, "\n...")
+	if shown != "\xffé" {
+		t.Errorf("expected the split character to be dropped, got %q", shown)
+	}
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"time"
+)
+
+// Aggregate summarizes the responses of a request sent several times in a row.
+type Aggregate struct {
+	Count     int
+	Successes int
+	// Failures counts the sends that returned an error, they have no response.
+	Failures int
+
+	MinLatency time.Duration
+	AvgLatency time.Duration
+	MaxLatency time.Duration
+
+	// StatusCodes maps each status code to the number of responses that had it.
+	StatusCodes map[int]int
+}
+
+// SendRequestRepeat sends the request n times one after the other. The responses are returned in order,
+// with nil in place of the sends that failed.
+func (s *Service) SendRequestRepeat(requestID, activeEnvironmentID string, n int) ([]*Response, Aggregate) {
+	responses := make([]*Response, 0, n)
+	for i := 0; i < n; i++ {
+		res, err := s.SendRequest(requestID, activeEnvironmentID)
+		if err != nil {
+			res = nil
+		}
+		responses = append(responses, res)
+	}
+
+	return responses, aggregate(responses)
+}
+
+func aggregate(responses []*Response) Aggregate {
+	agg := Aggregate{
+		Count:       len(responses),
+		StatusCodes: make(map[int]int),
+	}
+
+	var total time.Duration
+	for _, res := range responses {
+		if res == nil {
+			agg.Failures++
+			continue
+		}
+
+		if res.StatusCode >= 200 && res.StatusCode < 300 {
+			agg.Successes++
+		}
+		agg.StatusCodes[res.StatusCode]++
+
+		if agg.MinLatency == 0 || res.TimePassed < agg.MinLatency {
+			agg.MinLatency = res.TimePassed
+		}
+		agg.MaxLatency = max(agg.MaxLatency, res.TimePassed)
+		total += res.TimePassed
+	}
+
+	if received := agg.Count - agg.Failures; received > 0 {
+		agg.AvgLatency = total / time.Duration(received)
+	}
+
+	return agg
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+func TestAggregate(t *testing.T) {
+	responses := []*Response{
+		{StatusCode: http.StatusOK, TimePassed: 10 * time.Millisecond},
+		nil,
+		{StatusCode: http.StatusInternalServerError, TimePassed: 40 * time.Millisecond},
+		{StatusCode: http.StatusOK, TimePassed: 30 * time.Millisecond},
+	}
+
+	want := Aggregate{
+		Count:      4,
+		Successes:  2,
+		Failures:   1,
+		MinLatency: 10 * time.Millisecond,
+		AvgLatency: 80 * time.Millisecond / 3,
+		MaxLatency: 40 * time.Millisecond,
+		StatusCodes: map[int]int{
+			http.StatusOK:                  2,
+			http.StatusInternalServerError: 1,
+		},
+	}
+
+	if got := aggregate(responses); !reflect.DeepEqual(got, want) {
+		t.Errorf("aggregate() = %+v, want %+v", got, want)
+	}
+}
+
+func TestService_SendRequestRepeat(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("repeat")
+	req.Spec.HTTP.URL = server.URL
+	requests.AddRequest(req)
+
+	service := New(requests, state.NewEnvironments(nil), state.NewAuthProfiles(nil), nil)
+	responses, agg := service.SendRequestRepeat(req.MetaData.ID, "", 3)
+
+	if len(responses) != 3 || calls != 3 {
+		t.Fatalf("expected 3 sends, got %d responses and %d calls", len(responses), calls)
+	}
+
+	if agg.Successes != 2 || agg.StatusCodes[http.StatusServiceUnavailable] != 1 {
+		t.Errorf("unexpected aggregate %+v", agg)
+	}
+}
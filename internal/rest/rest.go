@@ -2,18 +2,25 @@ package rest
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/safemap"
 	"github.com/chapar-rest/chapar/internal/state"
 	"github.com/google/uuid"
 )
@@ -28,26 +35,87 @@ type Response struct {
 
 	IsJSON bool
 	JSON   string
+
+	// Snapshot is the result of the snapshot check, nil when the request has no snapshot.
+	Snapshot *SnapshotResult
+
+	// SentRequest is the request as it was sent, to save it along with the response as a fixture.
+	SentRequest *FixtureRequest
+
+	// TraceID is the trace id of the injected traceparent header, empty when none was injected.
+	TraceID string
+
+	// Warnings lists the warning thresholds from the preferences the request went over.
+	Warnings []string
+
+	// RemainingDeadline is how much of the request timeout was left when the response was read,
+	// zero when there is no timeout.
+	RemainingDeadline time.Duration
 }
 
+// ErrTimeout is returned, wrapped, when a request does not complete within the request timeout.
+var ErrTimeout = errors.New("request timed out")
+
 type Service struct {
 	requests     *state.Requests
 	environments *state.Environments
+	authProfiles *state.AuthProfiles
+	preferences  *domain.Preferences
+
+	// commandOutputs caches the output of auth commands by command line
+	commandOutputs *safemap.Map[commandOutput]
 }
 
-func New(requests *state.Requests, environments *state.Environments) *Service {
+func New(requests *state.Requests, environments *state.Environments, authProfiles *state.AuthProfiles, preferences *domain.Preferences) *Service {
 	return &Service{
 		requests:     requests,
 		environments: environments,
+		authProfiles: authProfiles,
+		preferences:  preferences,
+
+		commandOutputs: safemap.New[commandOutput](),
 	}
 }
 
+// timeout returns the request timeout from the preferences, zero means no timeout.
+func (s *Service) timeout() time.Duration {
+	if s.preferences == nil {
+		return 0
+	}
+
+	return time.Duration(s.preferences.Spec.RequestTimeoutMilliseconds) * time.Millisecond
+}
+
 func (s *Service) SendRequest(requestID, activeEnvironmentID string) (*Response, error) {
+	return s.SendRequestContext(context.Background(), requestID, activeEnvironmentID)
+}
+
+// SendRequestContext is SendRequest with a context, cancelling it aborts the request in flight.
+func (s *Service) SendRequestContext(ctx context.Context, requestID, activeEnvironmentID string) (*Response, error) {
 	req := s.requests.GetRequest(requestID)
 	if req == nil {
 		return nil, fmt.Errorf("request with id %s not found", requestID)
 	}
 
+	return s.send(ctx, req, s.environmentID(req, activeEnvironmentID), true)
+}
+
+// environmentID returns the id of the environment the request runs against: the environment set
+// on the request wins over the selected one, unless it was removed since.
+func (s *Service) environmentID(req *domain.Request, activeEnvironmentID string) string {
+	if req.Spec.HTTP == nil || req.Spec.HTTP.EnvironmentOverride == "" {
+		return activeEnvironmentID
+	}
+
+	if s.environments.GetEnvironment(req.Spec.HTTP.EnvironmentOverride) == nil {
+		return activeEnvironmentID
+	}
+
+	return req.Spec.HTTP.EnvironmentOverride
+}
+
+// send sends the request against the given environment, checking its snapshot when asked to.
+func (s *Service) send(ctx context.Context, req *domain.Request, activeEnvironmentID string, checkSnapshot bool) (*Response, error) {
 	// clone the request to make sure we do not modify the original request
 	r := req.Clone()
 
@@ -60,7 +128,12 @@ func (s *Service) SendRequest(requestID, activeEnvironmentID string) (*Response,
 		}
 	}
 
-	response, err := s.sendRequest(r.Spec.HTTP, activeEnvironment)
+	applyDefaultAuth(r.Spec.HTTP.Request, activeEnvironment)
+	if err := s.resolveAuthProfile(r.Spec.HTTP.Request); err != nil {
+		return nil, err
+	}
+
+	response, err := s.sendRequest(ctx, r.Spec.HTTP, activeEnvironment)
 	if err != nil {
 		return nil, err
 	}
@@ -70,9 +143,48 @@ func (s *Service) SendRequest(requestID, activeEnvironmentID string) (*Response,
 		return nil, err
 	}
 
+	// use the clone, the request in the state belongs to the UI goroutine
+	if checkSnapshot && r.Spec.HTTP.Snapshot != nil {
+		response.Snapshot = snapshotResult(r.Spec.HTTP.Snapshot, string(response.Body))
+	}
+
 	return response, nil
 }
 
+// applyDefaultAuth uses the default auth of the environment for requests without auth,
+// the auth set on the request always wins.
+func applyDefaultAuth(req *domain.HTTPRequest, env *domain.Environment) {
+	if req == nil || env == nil || env.Spec.DefaultAuth == nil {
+		return
+	}
+
+	if req.Auth.Type != "" && req.Auth.Type != domain.AuthTypeNone {
+		return
+	}
+
+	req.Auth = env.Spec.DefaultAuth.Clone()
+}
+
+// resolveAuthProfile replaces a profile reference with the auth of that profile,
+// it happens on every send so profile changes apply to all the requests using it.
+func (s *Service) resolveAuthProfile(req *domain.HTTPRequest) error {
+	if req == nil || req.Auth.Type != domain.AuthTypeProfile {
+		return nil
+	}
+
+	var profile *domain.AuthProfile
+	if s.authProfiles != nil {
+		profile = s.authProfiles.GetAuthProfile(req.Auth.ProfileID)
+	}
+
+	if profile == nil {
+		return fmt.Errorf("auth profile with id %s not found", req.Auth.ProfileID)
+	}
+
+	req.Auth = profile.Spec.Clone()
+	return nil
+}
+
 func (s *Service) handlePostRequest(r domain.PostRequest, response *Response, env *domain.Environment) error {
 	if r == (domain.PostRequest{}) {
 		return nil
@@ -164,20 +276,21 @@ func (s *Service) handlePostRequestFromCookie(r domain.PostRequest, response *Re
 	return nil
 }
 
-func (s *Service) sendRequest(req *domain.HTTPRequestSpec, e *domain.Environment) (*Response, error) {
+func (s *Service) sendRequest(ctx context.Context, req *domain.HTTPRequestSpec, e *domain.Environment) (*Response, error) {
 	// prepare request
 	// - apply environment
 	// - apply variables
 	// - apply authentication (if any) is not already applied to the headers
 
-	if e == nil {
-		applyVariables(req, nil)
-	} else {
-		env := e.Clone()
-		applyVariables(req, &env.Spec)
+	var envSpec *domain.EnvSpec
+	if e != nil {
+		envSpec = &e.Clone().Spec
 	}
 
-	httpReq, err := http.NewRequest(req.Method, req.URL, nil)
+	variables := variableValues(req, envSpec)
+	applyVariableValues(req, variables)
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -189,6 +302,11 @@ func (s *Service) sendRequest(req *domain.HTTPRequestSpec, e *domain.Environment
 		}
 	}
 
+	traceID, err := s.injectTraceparent(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
 	// apply path params as single brace
 	for _, p := range req.Request.PathParams {
 		httpReq.URL.Path = strings.ReplaceAll(httpReq.URL.Path, "{"+p.Key+"}", p.Value)
@@ -297,6 +415,30 @@ func (s *Service) sendRequest(req *domain.HTTPRequestSpec, e *domain.Environment
 		if req.Request.Auth.APIKeyAuth != nil && req.Request.Auth.APIKeyAuth.Key != "" && req.Request.Auth.APIKeyAuth.Value != "" {
 			httpReq.Header.Add(req.Request.Auth.APIKeyAuth.Key, req.Request.Auth.APIKeyAuth.Value)
 		}
+
+		if req.Request.Auth.Type == domain.AuthTypeJWT && req.Request.Auth.JWTAuth != nil {
+			token, err := SignJWT(req.Request.Auth.JWTAuth, time.Now())
+			if err != nil {
+				return nil, err
+			}
+			httpReq.Header.Add("Authorization", "Bearer "+token)
+		}
+
+		if req.Request.Auth.Type == domain.AuthTypeCommand && req.Request.Auth.CommandAuth != nil {
+			auth := req.Request.Auth.CommandAuth
+			output, err := s.runAuthCommand(ctx, auth, variables)
+			if err != nil {
+				return nil, err
+			}
+			httpReq.Header.Set(commandAuthHeader(auth), auth.Prefix+output)
+		}
+
+		// signing has to be last as it covers the headers and the body
+		if req.Request.Auth.Type == domain.AuthTypeAWSSigV4 && req.Request.Auth.AWSSigV4Auth != nil {
+			if err := SignSigV4(httpReq, req.Request.Auth.AWSSigV4Auth, time.Now()); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// send request
@@ -307,10 +449,23 @@ func (s *Service) sendRequest(req *domain.HTTPRequestSpec, e *domain.Environment
 	// - handle redirects
 	// - handle status code
 
+	sentRequest, err := newFixtureRequest(httpReq, authHeaderNames(req.Request.Auth)...)
+	if err != nil {
+		return nil, err
+	}
+
+	rawRequest := s.dumpRequest(httpReq)
+
 	// send request
+	timeout := s.timeout()
+	client := &http.Client{Timeout: timeout}
 	start := time.Now()
-	res, err := http.DefaultClient.Do(httpReq)
+	res, err := client.Do(httpReq)
 	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, fmt.Errorf("%w after %s: %w", ErrTimeout, timeout, err)
+		}
 		return nil, err
 	}
 
@@ -323,6 +478,8 @@ func (s *Service) sendRequest(req *domain.HTTPRequestSpec, e *domain.Environment
 	// measure time
 	elapsed := time.Since(start)
 
+	s.captureWire(rawRequest, res, body)
+
 	// handle response
 	response := &Response{
 		StatusCode: res.StatusCode,
@@ -331,6 +488,14 @@ func (s *Service) sendRequest(req *domain.HTTPRequestSpec, e *domain.Environment
 		Body:       body,
 		TimePassed: elapsed,
 		IsJSON:     false,
+
+		SentRequest: sentRequest,
+		TraceID:     traceID,
+		Warnings:    s.warnings(len(sentRequest.Body), elapsed),
+	}
+
+	if timeout > 0 {
+		response.RemainingDeadline = max(timeout-elapsed, 0)
 	}
 
 	if IsJSON(string(body)) {
@@ -350,14 +515,71 @@ func (s *Service) sendRequest(req *domain.HTTPRequestSpec, e *domain.Environment
 	return response, nil
 }
 
-func applyVariables(req *domain.HTTPRequestSpec, env *domain.EnvSpec) *domain.HTTPRequestSpec {
-	// apply internal variables to environment
-	// apply environment to request
-	variables := map[string]string{
+// dynamicVariables returns the built-in variables, evaluated at the time of the call.
+func dynamicVariables() map[string]string {
+	return map[string]string{
 		"randomUUID4":   uuid.NewString(),
 		"timeNow":       time.Now().UTC().Format(time.RFC3339),
 		"unixTimestamp": strconv.FormatInt(time.Now().UTC().Unix(), 10),
 	}
+}
+
+// VariableNames returns the sorted names of the enabled variables available to the given request,
+// sent with the given environment, falling back to the active one when envID is empty.
+// The request variables are included when requestID is not empty.
+// It backs the {{ autocompletion in the editors.
+func (s *Service) VariableNames(requestID, envID string) []string {
+	names := make([]string, 0)
+	add := func(values []domain.KeyValue) {
+		for _, kv := range values {
+			if kv.Enable && kv.Key != "" && !slices.Contains(names, kv.Key) {
+				names = append(names, kv.Key)
+			}
+		}
+	}
+
+	for k := range dynamicVariables() {
+		names = append(names, k)
+	}
+
+	var req *domain.Request
+	if requestID != "" {
+		req = s.requests.GetRequest(requestID)
+	}
+
+	// the environment set on the request wins over the selected one, as when sending it
+	if req != nil {
+		envID = s.environmentID(req, envID)
+	}
+
+	env := s.environments.GetActiveEnvironment()
+	if envID != "" {
+		env = s.environments.GetEnvironment(envID)
+	}
+
+	if env != nil {
+		add(env.Spec.Values)
+	}
+
+	if req != nil && req.Spec.HTTP != nil && req.Spec.HTTP.Request != nil {
+		add(req.Spec.HTTP.Request.Variables)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func applyVariables(req *domain.HTTPRequestSpec, env *domain.EnvSpec) *domain.HTTPRequestSpec {
+	return applyVariableValues(req, variableValues(req, env))
+}
+
+// variableValues returns the values of the variables available to the request: the dynamic
+// variables, then the environment ones and then the request ones, the latter winning.
+func variableValues(req *domain.HTTPRequestSpec, env *domain.EnvSpec) map[string]string {
+	// apply internal variables to environment
+	// apply environment to request
+	dynamic := dynamicVariables()
+	variables := maps.Clone(dynamic)
 
 	// apply environment variables if any
 	if env != nil {
@@ -377,6 +599,31 @@ func applyVariables(req *domain.HTTPRequestSpec, env *domain.EnvSpec) *domain.HT
 		}
 	}
 
+	if req.Request == nil {
+		return variables
+	}
+
+	// request variables come last so they override the environment ones
+	for _, kv := range req.Request.Variables {
+		if !kv.Enable || kv.Key == "" {
+			continue
+		}
+
+		value := kv.Value
+		for k, v := range dynamic {
+			value = strings.ReplaceAll(value, "{{"+k+"}}", v)
+		}
+		variables[kv.Key] = value
+	}
+
+	return variables
+}
+
+func applyVariableValues(req *domain.HTTPRequestSpec, variables map[string]string) *domain.HTTPRequestSpec {
+	if req.Request == nil {
+		return req
+	}
+
 	// apply variables to request
 	for k, v := range variables {
 		for i, kv := range req.Request.Headers {
@@ -451,6 +698,25 @@ func applyVariables(req *domain.HTTPRequestSpec, env *domain.EnvSpec) *domain.HT
 			}
 		}
 
+		if req.Request.Auth != (domain.Auth{}) && req.Request.Auth.JWTAuth != nil {
+			if strings.Contains(req.Request.Auth.JWTAuth.Secret, "{{"+k+"}}") {
+				req.Request.Auth.JWTAuth.Secret = strings.ReplaceAll(req.Request.Auth.JWTAuth.Secret, "{{"+k+"}}", v)
+			}
+
+			if strings.Contains(req.Request.Auth.JWTAuth.Claims, "{{"+k+"}}") {
+				req.Request.Auth.JWTAuth.Claims = strings.ReplaceAll(req.Request.Auth.JWTAuth.Claims, "{{"+k+"}}", v)
+			}
+		}
+
+		if req.Request.Auth != (domain.Auth{}) && req.Request.Auth.AWSSigV4Auth != nil {
+			aws := req.Request.Auth.AWSSigV4Auth
+			aws.AccessKey = strings.ReplaceAll(aws.AccessKey, "{{"+k+"}}", v)
+			aws.SecretKey = strings.ReplaceAll(aws.SecretKey, "{{"+k+"}}", v)
+			aws.SessionToken = strings.ReplaceAll(aws.SessionToken, "{{"+k+"}}", v)
+			aws.Region = strings.ReplaceAll(aws.Region, "{{"+k+"}}", v)
+			aws.Service = strings.ReplaceAll(aws.Service, "{{"+k+"}}", v)
+		}
+
 	}
 	return req
 }
@@ -468,6 +734,33 @@ func PrettyJSON(data []byte) (string, error) {
 	return out.String(), nil
 }
 
+// FormatJSON re-indents text. Syntax errors report the line and column they occur at.
+func FormatJSON(text string) (string, error) {
+	out, err := PrettyJSON([]byte(text))
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, col := position(text, syntaxErr.Offset)
+			return "", fmt.Errorf("invalid json at line %d, column %d: %w", line, col, err)
+		}
+		return "", err
+	}
+	return out, nil
+}
+
+// position converts a json.SyntaxError offset, which counts the offending byte,
+// to the 1-based line and column of that byte.
+func position(text string, offset int64) (int, int) {
+	if offset > int64(len(text)) {
+		offset = int64(len(text))
+	}
+
+	before := text[:offset]
+	line := strings.Count(before, "\n") + 1
+	col := len(before) - strings.LastIndex(before, "\n") - 1
+	return line, col
+}
+
 func ParseJSON(text string) (map[string]any, error) {
 	var js map[string]any
 	if err := json.Unmarshal([]byte(text), &js); err != nil {
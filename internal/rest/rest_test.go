@@ -1,9 +1,17 @@
 package rest
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
 	"github.com/google/uuid"
 )
 
@@ -31,3 +39,364 @@ func Test_applyVariables(t *testing.T) {
 		t.Errorf("expected valid uuid but got %s", sampleEnv.Values[0].Value)
 	}
 }
+
+func Test_applyVariables_RequestVariables(t *testing.T) {
+	env := &domain.EnvSpec{
+		Values: []domain.KeyValue{
+			{Key: "host", Value: "env.example.com"},
+			{Key: "port", Value: "8080"},
+		},
+	}
+
+	req := &domain.HTTPRequestSpec{
+		URL: "http://{{host}}:{{port}}/{{id}}",
+		Request: &domain.HTTPRequest{
+			Variables: []domain.KeyValue{
+				{Key: "host", Value: "local.example.com", Enable: true},
+				{Key: "port", Value: "9090", Enable: false},
+				{Key: "id", Value: "{{unixTimestamp}}", Enable: true},
+			},
+		},
+	}
+
+	applyVariables(req, env)
+
+	if !strings.HasPrefix(req.URL, "http://local.example.com:8080/") {
+		t.Errorf("expected the request variable to override the environment one, got %s", req.URL)
+	}
+
+	if strings.Contains(req.URL, "{{") {
+		t.Errorf("expected dynamic variables to be applied to request variables, got %s", req.URL)
+	}
+}
+
+func TestService_SendRequest_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("timeout")
+	req.Spec.HTTP.URL = server.URL
+	requests.AddRequest(req)
+
+	preferences := domain.NewPreferences()
+	preferences.Spec.RequestTimeoutMilliseconds = 50
+
+	service := New(requests, state.NewEnvironments(nil), state.NewAuthProfiles(nil), preferences)
+	if _, err := service.SendRequest(req.MetaData.ID, ""); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected timeout error but got %v", err)
+	}
+
+	// zero means no timeout
+	preferences.Spec.RequestTimeoutMilliseconds = 0
+	if _, err := service.SendRequest(req.MetaData.ID, ""); err != nil {
+		t.Errorf("expected no error but got %v", err)
+	}
+}
+
+func TestService_SendRequest_RemainingDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("fast")
+	req.Spec.HTTP.URL = server.URL
+	requests.AddRequest(req)
+
+	preferences := domain.NewPreferences()
+	preferences.Spec.RequestTimeoutMilliseconds = 5000
+
+	service := New(requests, state.NewEnvironments(nil), state.NewAuthProfiles(nil), preferences)
+	res, err := service.SendRequest(req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if res.RemainingDeadline <= 4*time.Second || res.RemainingDeadline > 5*time.Second {
+		t.Errorf("expected most of the 5s timeout to remain, got %s", res.RemainingDeadline)
+	}
+
+	if res.RemainingDeadline+res.TimePassed > 5*time.Second {
+		t.Errorf("expected the remaining deadline and the elapsed time to fit in the timeout")
+	}
+
+	// no timeout, no deadline
+	preferences.Spec.RequestTimeoutMilliseconds = 0
+	res, err = service.SendRequest(req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if res.RemainingDeadline != 0 {
+		t.Errorf("expected no remaining deadline without a timeout, got %s", res.RemainingDeadline)
+	}
+}
+
+func TestService_SendRequestContext_Cancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("cancel")
+	req.Spec.HTTP.URL = server.URL
+	requests.AddRequest(req)
+
+	service := New(requests, state.NewEnvironments(nil), state.NewAuthProfiles(nil), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	if _, err := service.SendRequestContext(ctx, req.MetaData.ID, ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled but got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the request to be aborted, it took %s", elapsed)
+	}
+}
+
+func TestService_SendRequest_AuthProfile(t *testing.T) {
+	received := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	profile := domain.NewAuthProfile("shared")
+	profile.Spec = domain.Auth{
+		Type:      domain.AuthTypeToken,
+		TokenAuth: &domain.TokenAuth{Token: "shared-token"},
+	}
+	authProfiles := state.NewAuthProfiles(nil)
+	authProfiles.AddAuthProfile(profile)
+
+	requests := state.NewRequests(nil)
+	ids := make([]string, 0)
+	for _, name := range []string{"first", "second"} {
+		req := domain.NewRequest(name)
+		req.Spec.HTTP.URL = server.URL
+		req.Spec.HTTP.Request.Auth = domain.Auth{Type: domain.AuthTypeProfile, ProfileID: profile.MetaData.ID}
+		requests.AddRequest(req)
+		ids = append(ids, req.MetaData.ID)
+	}
+
+	service := New(requests, state.NewEnvironments(nil), authProfiles, nil)
+	for _, id := range ids {
+		if _, err := service.SendRequest(id, ""); err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+	}
+
+	// changing the profile applies to every request using it
+	profile.Spec.TokenAuth.Token = "rotated-token"
+	if _, err := service.SendRequest(ids[0], ""); err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	want := []string{"Bearer shared-token", "Bearer shared-token", "Bearer rotated-token"}
+	if !reflect.DeepEqual(received, want) {
+		t.Errorf("expected Authorization headers %v, got %v", want, received)
+	}
+
+	if requests.GetRequest(ids[0]).Spec.HTTP.Request.Auth.Type != domain.AuthTypeProfile {
+		t.Errorf("expected the stored request to keep referencing the profile")
+	}
+}
+
+func TestService_SendRequest_AuthProfileNotFound(t *testing.T) {
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("missing profile")
+	req.Spec.HTTP.Request.Auth = domain.Auth{Type: domain.AuthTypeProfile, ProfileID: "missing"}
+	requests.AddRequest(req)
+
+	service := New(requests, state.NewEnvironments(nil), state.NewAuthProfiles(nil), nil)
+	if _, err := service.SendRequest(req.MetaData.ID, ""); err == nil {
+		t.Errorf("expected an error for a missing auth profile")
+	}
+}
+
+func TestService_SendRequest_EnvironmentOverride(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("X-Env")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	environments := state.NewEnvironments(nil)
+	selected := domain.NewEnvironment("selected")
+	selected.Spec.Values = []domain.KeyValue{{Key: "env", Value: "selected", Enable: true}}
+	environments.AddEnvironment(selected, state.SourceController)
+
+	override := domain.NewEnvironment("override")
+	override.Spec.Values = []domain.KeyValue{{Key: "env", Value: "override", Enable: true}}
+	environments.AddEnvironment(override, state.SourceController)
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("override")
+	req.Spec.HTTP.URL = server.URL
+	req.Spec.HTTP.Request.Headers = []domain.KeyValue{{Key: "X-Env", Value: "{{env}}", Enable: true}}
+	requests.AddRequest(req)
+
+	service := New(requests, environments, state.NewAuthProfiles(nil), nil)
+	if _, err := service.SendRequest(req.MetaData.ID, selected.MetaData.ID); err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if received != "selected" {
+		t.Errorf("expected the selected environment without an override, got %q", received)
+	}
+
+	req.Spec.HTTP.EnvironmentOverride = override.MetaData.ID
+	if _, err := service.SendRequest(req.MetaData.ID, selected.MetaData.ID); err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if received != "override" {
+		t.Errorf("expected the override environment to be applied, got %q", received)
+	}
+
+	// a removed override environment falls back to the selected one
+	if err := environments.RemoveEnvironment(override, state.SourceController, true); err != nil {
+		t.Fatalf("failed to remove environment: %v", err)
+	}
+
+	if _, err := service.SendRequest(req.MetaData.ID, selected.MetaData.ID); err != nil {
+		t.Fatalf("failed to send request after removing the override environment: %v", err)
+	}
+
+	if received != "selected" {
+		t.Errorf("expected the selected environment after removing the override, got %q", received)
+	}
+}
+
+func TestService_SendRequest_EnvironmentDefaultAuth(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	environments := state.NewEnvironments(nil)
+	env := domain.NewEnvironment("staging")
+	env.Spec.Values = []domain.KeyValue{{Key: "token", Value: "env-token", Enable: true}}
+	env.Spec.DefaultAuth = &domain.Auth{
+		Type:      domain.AuthTypeToken,
+		TokenAuth: &domain.TokenAuth{Token: "{{token}}"},
+	}
+	environments.AddEnvironment(env, state.SourceController)
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("default auth")
+	req.Spec.HTTP.URL = server.URL
+	req.Spec.HTTP.Request.Auth = domain.Auth{Type: domain.AuthTypeNone}
+	requests.AddRequest(req)
+
+	service := New(requests, environments, state.NewAuthProfiles(nil), nil)
+	if _, err := service.SendRequest(req.MetaData.ID, env.MetaData.ID); err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if received != "Bearer env-token" {
+		t.Errorf("expected the environment default auth, got %q", received)
+	}
+
+	// the auth of the request wins over the environment default
+	req.Spec.HTTP.Request.Auth = domain.Auth{
+		Type:      domain.AuthTypeToken,
+		TokenAuth: &domain.TokenAuth{Token: "request-token"},
+	}
+	if _, err := service.SendRequest(req.MetaData.ID, env.MetaData.ID); err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if received != "Bearer request-token" {
+		t.Errorf("expected the request auth, got %q", received)
+	}
+
+	if req.Spec.HTTP.Request.Auth.TokenAuth.Token != "request-token" || env.Spec.DefaultAuth.TokenAuth.Token != "{{token}}" {
+		t.Errorf("expected the stored request and environment to be unchanged")
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	got, err := FormatJSON(`{"name":"chapar","tags":["a","b"]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "{\n    \"name\": \"chapar\",\n    \"tags\": [\n        \"a\",\n        \"b\"\n    ]\n}"
+	if got != want {
+		t.Errorf("FormatJSON() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatJSON_InvalidPosition(t *testing.T) {
+	_, err := FormatJSON("{\n  \"a\": 1,\n  x\n}")
+	if err == nil {
+		t.Fatal("expected an error for invalid json")
+	}
+
+	if !strings.Contains(err.Error(), "line 3, column 3") {
+		t.Errorf("expected error to point at line 3, column 3, got %q", err)
+	}
+}
+
+func TestService_VariableNames(t *testing.T) {
+	environments := state.NewEnvironments(nil)
+	env := domain.NewEnvironment("dev")
+	env.Spec.Values = []domain.KeyValue{
+		{Key: "baseUrl", Value: "http://localhost", Enable: true},
+		{Key: "token", Value: "secret", Enable: true},
+		// overriding a dynamic variable must not list it twice
+		{Key: "timeNow", Value: "now", Enable: true},
+		{Key: "disabled", Value: "off"},
+	}
+	environments.AddEnvironment(env, state.SourceController)
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("users")
+	req.Spec.HTTP.Request.Variables = []domain.KeyValue{
+		{Key: "userId", Value: "42", Enable: true},
+		{Key: "token", Value: "override", Enable: true},
+		{Key: "draft", Value: "x"},
+	}
+	requests.AddRequest(req)
+
+	service := New(requests, environments, state.NewAuthProfiles(nil), nil)
+
+	want := []string{"baseUrl", "randomUUID4", "timeNow", "token", "unixTimestamp"}
+	if got := service.VariableNames("", env.MetaData.ID); !reflect.DeepEqual(got, want) {
+		t.Errorf("VariableNames() = %v, want %v", got, want)
+	}
+
+	// the enabled request variables are merged in
+	want = []string{"baseUrl", "randomUUID4", "timeNow", "token", "unixTimestamp", "userId"}
+	if got := service.VariableNames(req.MetaData.ID, env.MetaData.ID); !reflect.DeepEqual(got, want) {
+		t.Errorf("VariableNames() = %v, want %v", got, want)
+	}
+
+	// without an environment only the dynamic variables are available
+	want = []string{"randomUUID4", "timeNow", "unixTimestamp"}
+	if got := service.VariableNames("", ""); !reflect.DeepEqual(got, want) {
+		t.Errorf("VariableNames() = %v, want %v", got, want)
+	}
+
+	environments.SetActiveEnvironment(env)
+	if got := service.VariableNames("", ""); len(got) != 5 {
+		t.Errorf("expected the active environment to be used, got %v", got)
+	}
+}
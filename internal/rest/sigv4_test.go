@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+// the "get-vanilla" case of the AWS Signature Version 4 test suite
+func TestSignSigV4(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	auth := &domain.AWSSigV4Auth{
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:    "us-east-1",
+		Service:   "service",
+	}
+
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	if err := SignSigV4(req, auth, now); err != nil {
+		t.Fatalf("failed to sign request: %v", err)
+	}
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, " +
+		"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %q, want %q", got, want)
+	}
+
+	if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+		t.Errorf("X-Amz-Date = %q, want %q", got, "20150830T123600Z")
+	}
+}
+
+func TestSignSigV4_KeepsBody(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://example.amazonaws.com/items?b=2&a=1", io.NopCloser(strings.NewReader("hello")))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	auth := &domain.AWSSigV4Auth{AccessKey: "key", SecretKey: "secret", Region: "eu-west-1", Service: "execute-api"}
+	if err := SignSigV4(req, auth, time.Now()); err != nil {
+		t.Fatalf("failed to sign request: %v", err)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+
+	if string(body) != "hello" {
+		t.Errorf("expected body to be kept, got %q", body)
+	}
+
+	if req.ContentLength != 5 {
+		t.Errorf("expected content length 5, got %d", req.ContentLength)
+	}
+}
+
+func Test_canonicalSigV4URI(t *testing.T) {
+	tests := []struct {
+		url     string
+		service string
+		want    string
+	}{
+		{url: "https://example.amazonaws.com", service: "execute-api", want: "/"},
+		{url: "https://example.amazonaws.com/items/a b", service: "execute-api", want: "/items/a%2520b"},
+		{url: "https://example.amazonaws.com/items/a b", service: "s3", want: "/items/a%20b"},
+		{url: "https://example.amazonaws.com/a%2Fb/c", service: "execute-api", want: "/a%252Fb/c"},
+		{url: "https://example.amazonaws.com/users/me@example.com", service: "s3", want: "/users/me%40example.com"},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", tt.url, err)
+		}
+
+		if got := canonicalSigV4URI(u, tt.service); got != tt.want {
+			t.Errorf("canonicalSigV4URI(%s, %s) = %s, want %s", tt.url, tt.service, got, tt.want)
+		}
+	}
+}
+
+func Test_applyVariables_SigV4(t *testing.T) {
+	req := domain.NewRequest("aws").Spec.HTTP
+	req.Request.Auth = domain.Auth{
+		Type: domain.AuthTypeAWSSigV4,
+		AWSSigV4Auth: &domain.AWSSigV4Auth{
+			AccessKey: "{{accessKey}}",
+			Region:    "{{region}}",
+			Service:   "{{service}}",
+		},
+	}
+
+	env := &domain.EnvSpec{Values: []domain.KeyValue{
+		{Key: "accessKey", Value: "AKIDEXAMPLE", Enable: true},
+		{Key: "region", Value: "eu-west-1", Enable: true},
+		{Key: "service", Value: "execute-api", Enable: true},
+	}}
+
+	aws := applyVariables(req, env).Request.Auth.AWSSigV4Auth
+	if aws.AccessKey != "AKIDEXAMPLE" || aws.Region != "eu-west-1" || aws.Service != "execute-api" {
+		t.Errorf("expected the variables to be applied, got %+v", aws)
+	}
+}
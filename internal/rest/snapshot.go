@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"fmt"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+type SnapshotStatus string
+
+const (
+	// SnapshotRecorded means the request had an empty snapshot and the response became its snapshot.
+	SnapshotRecorded SnapshotStatus = "recorded"
+	SnapshotPassed   SnapshotStatus = "passed"
+	SnapshotFailed   SnapshotStatus = "failed"
+)
+
+type SnapshotResult struct {
+	Status SnapshotStatus
+	// Recorded is the snapshot made from the response when the status is SnapshotRecorded. It is not
+	// set on the request, as sending happens off the UI goroutine; the caller applies it with UpdateSnapshot.
+	Recorded *domain.ResponseSnapshot
+	// Diff goes from the snapshot to the actual response, it is only set when the check failed.
+	Diff []DiffLine
+}
+
+// CompareSnapshot compares the actual response body with the expected one, both canonicalized when they are JSON.
+func CompareSnapshot(expected, actual string) *SnapshotResult {
+	if normalizeForDiff(expected) == normalizeForDiff(actual) {
+		return &SnapshotResult{Status: SnapshotPassed}
+	}
+
+	return &SnapshotResult{
+		Status: SnapshotFailed,
+		Diff:   Diff(expected, actual),
+	}
+}
+
+// UpdateSnapshot makes body the expected response of the request. Like any other edit
+// it only changes the request in memory, saving the request persists it.
+func (s *Service) UpdateSnapshot(requestID, body string) (*domain.ResponseSnapshot, error) {
+	req := s.requests.GetRequest(requestID)
+	if req == nil {
+		return nil, fmt.Errorf("request with id %s not found", requestID)
+	}
+
+	req.Spec.HTTP.Snapshot = &domain.ResponseSnapshot{Body: normalizeForDiff(body)}
+	if err := s.requests.UpdateRequest(req, true); err != nil {
+		return nil, err
+	}
+
+	return req.Spec.HTTP.Snapshot, nil
+}
+
+// snapshotResult compares the response with the snapshot, an empty snapshot records the response instead.
+func snapshotResult(snapshot *domain.ResponseSnapshot, body string) *SnapshotResult {
+	if snapshot.Body == "" {
+		return &SnapshotResult{
+			Status:   SnapshotRecorded,
+			Recorded: &domain.ResponseSnapshot{Body: normalizeForDiff(body)},
+		}
+	}
+
+	return CompareSnapshot(snapshot.Body, body)
+}
@@ -0,0 +1,105 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+func TestCompareSnapshot(t *testing.T) {
+	result := CompareSnapshot(`{"id":1,"name":"chapar"}`, `{"name":"chapar","id":1.0}`)
+	if result.Status != SnapshotPassed {
+		t.Errorf("expected equal documents to pass, got %s", result.Status)
+	}
+
+	result = CompareSnapshot(`{"id":1}`, `{"id":2}`)
+	if result.Status != SnapshotFailed {
+		t.Fatalf("expected different documents to fail, got %s", result.Status)
+	}
+
+	changed := 0
+	for _, l := range result.Diff {
+		if l.Kind != DiffEqual {
+			changed++
+		}
+	}
+
+	if changed != 2 {
+		t.Errorf("expected one removed and one added line, got %v", result.Diff)
+	}
+}
+
+func TestService_SendRequest_Snapshot(t *testing.T) {
+	body := `{"status":"ok"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("snapshot")
+	req.Spec.HTTP.URL = server.URL
+	requests.AddRequest(req)
+
+	service := New(requests, state.NewEnvironments(nil), state.NewAuthProfiles(nil), nil)
+
+	// without a snapshot nothing is checked
+	res, err := service.SendRequest(req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	if res.Snapshot != nil {
+		t.Errorf("expected no snapshot result, got %v", res.Snapshot)
+	}
+
+	// the first run records the snapshot
+	req.Spec.HTTP.Snapshot = &domain.ResponseSnapshot{}
+	res, err = service.SendRequest(req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	if res.Snapshot == nil || res.Snapshot.Status != SnapshotRecorded || res.Snapshot.Recorded == nil {
+		t.Fatalf("expected the snapshot to be recorded, got %v", res.Snapshot)
+	}
+	if req.Spec.HTTP.Snapshot.Body != "" {
+		t.Errorf("expected sending to leave the request in the state untouched")
+	}
+
+	// the caller applies the recorded snapshot
+	if _, err := service.UpdateSnapshot(req.MetaData.ID, res.Snapshot.Recorded.Body); err != nil {
+		t.Fatalf("failed to update snapshot: %v", err)
+	}
+
+	res, err = service.SendRequest(req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	if res.Snapshot.Status != SnapshotPassed {
+		t.Errorf("expected the snapshot to match, got %s", res.Snapshot.Status)
+	}
+
+	body = `{"status":"degraded"}`
+	res, err = service.SendRequest(req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	if res.Snapshot.Status != SnapshotFailed || len(res.Snapshot.Diff) == 0 {
+		t.Errorf("expected the snapshot to fail with a diff, got %v", res.Snapshot)
+	}
+
+	// updating the snapshot accepts the new response
+	if _, err := service.UpdateSnapshot(req.MetaData.ID, string(res.Body)); err != nil {
+		t.Fatalf("failed to update snapshot: %v", err)
+	}
+	res, err = service.SendRequest(req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	if res.Snapshot.Status != SnapshotPassed {
+		t.Errorf("expected the updated snapshot to match, got %s", res.Snapshot.Status)
+	}
+}
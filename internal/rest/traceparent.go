@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	traceparentHeader = "traceparent"
+	tracestateHeader  = "tracestate"
+)
+
+// NewTraceparent returns a W3C traceparent header value for a new sampled trace, along with its trace id.
+func NewTraceparent() (traceparent, traceID string, err error) {
+	ids := make([]byte, 24)
+	if _, err := rand.Read(ids); err != nil {
+		return "", "", err
+	}
+
+	traceID = hex.EncodeToString(ids[:16])
+	spanID := hex.EncodeToString(ids[16:])
+	return "00-" + traceID + "-" + spanID + "-01", traceID, nil
+}
+
+// injectTraceparent sets the traceparent and tracestate headers when enabled in the preferences
+// and returns the generated trace id. A traceparent set by the user is left as is.
+func (s *Service) injectTraceparent(req *http.Request) (string, error) {
+	if s.preferences == nil || !s.preferences.Spec.InjectTraceparent || req.Header.Get(traceparentHeader) != "" {
+		return "", nil
+	}
+
+	traceparent, traceID, err := NewTraceparent()
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set(traceparentHeader, traceparent)
+	if s.preferences.Spec.Tracestate != "" {
+		req.Header.Set(tracestateHeader, s.preferences.Spec.Tracestate)
+	}
+
+	return traceID, nil
+}
@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+var traceparentPattern = regexp.MustCompile(`^00-([0-9a-f]{32})-[0-9a-f]{16}-01$`)
+
+func TestService_SendRequest_InjectTraceparent(t *testing.T) {
+	var traceparent, tracestate string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		tracestate = r.Header.Get("tracestate")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("traced")
+	req.Spec.HTTP.URL = server.URL
+	requests.AddRequest(req)
+
+	preferences := domain.NewPreferences()
+	service := New(requests, state.NewEnvironments(nil), state.NewAuthProfiles(nil), preferences)
+
+	res, err := service.SendRequest(req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if traceparent != "" || res.TraceID != "" {
+		t.Errorf("expected no traceparent when disabled, got %q", traceparent)
+	}
+
+	preferences.Spec.InjectTraceparent = true
+	preferences.Spec.Tracestate = "vendor=value"
+
+	res, err = service.SendRequest(req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	match := traceparentPattern.FindStringSubmatch(traceparent)
+	if match == nil {
+		t.Fatalf("expected a valid traceparent, got %q", traceparent)
+	}
+
+	if match[1] != res.TraceID {
+		t.Errorf("expected trace id %s, got %s", match[1], res.TraceID)
+	}
+
+	if tracestate != "vendor=value" {
+		t.Errorf("expected tracestate vendor=value, got %q", tracestate)
+	}
+}
+
+func TestService_SendRequest_KeepsUserTraceparent(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	const userTraceparent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("traced")
+	req.Spec.HTTP.URL = server.URL
+	req.Spec.HTTP.Request.Headers = []domain.KeyValue{{Key: "traceparent", Value: userTraceparent, Enable: true}}
+	requests.AddRequest(req)
+
+	preferences := domain.NewPreferences()
+	preferences.Spec.InjectTraceparent = true
+	service := New(requests, state.NewEnvironments(nil), state.NewAuthProfiles(nil), preferences)
+
+	if _, err := service.SendRequest(req.MetaData.ID, ""); err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if traceparent != userTraceparent {
+		t.Errorf("expected the user traceparent to be kept, got %q", traceparent)
+	}
+}
@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+const (
+	ValidationFieldRequest = "request"
+	ValidationFieldMethod  = "method"
+	ValidationFieldURL     = "url"
+	ValidationFieldBody    = "body"
+)
+
+// ValidationIssue is a problem that would make sending the request fail.
+type ValidationIssue struct {
+	Field   string
+	Message string
+}
+
+// ValidateRequest checks the request, with the variables of the environment applied, before it is sent:
+// the method and a valid url are set, a JSON body parses and the files of the body exist.
+func (s *Service) ValidateRequest(requestID, activeEnvironmentID string) []ValidationIssue {
+	spec, err := s.resolve(requestID, activeEnvironmentID)
+	if err != nil {
+		return []ValidationIssue{{Field: ValidationFieldRequest, Message: err.Error()}}
+	}
+
+	var issues []ValidationIssue
+	if spec.Method == "" {
+		issues = append(issues, ValidationIssue{Field: ValidationFieldMethod, Message: "Select a method"})
+	}
+
+	if issue, ok := validateURL(spec.URL); !ok {
+		issues = append(issues, issue)
+	}
+
+	if spec.Request != nil {
+		issues = append(issues, validateBody(spec.Request.Body)...)
+	}
+
+	return issues
+}
+
+func validateURL(address string) (ValidationIssue, bool) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return ValidationIssue{Field: ValidationFieldURL, Message: "Enter a URL"}, false
+	}
+
+	if strings.Contains(address, "{{") {
+		return ValidationIssue{Field: ValidationFieldURL, Message: "URL has unresolved variables"}, false
+	}
+
+	u, err := url.Parse(address)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ValidationIssue{Field: ValidationFieldURL, Message: "URL must be an absolute http or https URL"}, false
+	}
+
+	return ValidationIssue{}, true
+}
+
+func validateBody(body domain.Body) []ValidationIssue {
+	var issues []ValidationIssue
+	switch body.Type {
+	case domain.BodyTypeJSON:
+		if strings.TrimSpace(body.Data) != "" && !json.Valid([]byte(body.Data)) {
+			issues = append(issues, ValidationIssue{Field: ValidationFieldBody, Message: "Body is not valid JSON"})
+		}
+	case domain.BodyTypeBinary:
+		if body.BinaryFilePath != "" && !fileExists(body.BinaryFilePath) {
+			issues = append(issues, ValidationIssue{Field: ValidationFieldBody, Message: "Body file " + body.BinaryFilePath + " does not exist"})
+		}
+	case domain.BodyTypeFormData:
+		for _, field := range body.FormData.Fields {
+			if field.Type != domain.FormFieldTypeFile {
+				continue
+			}
+
+			for _, f := range field.Files {
+				if !fileExists(f) {
+					issues = append(issues, ValidationIssue{Field: ValidationFieldBody, Message: "Form file " + f + " does not exist"})
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
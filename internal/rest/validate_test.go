@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+func TestService_ValidateRequest(t *testing.T) {
+	existing := filepath.Join(t.TempDir(), "body.bin")
+	if err := os.WriteFile(existing, []byte("data"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	env := domain.NewEnvironment("dev")
+	env.Spec.Values = []domain.KeyValue{{Key: "host", Value: "https://api.example.com", Enable: true}}
+	environments := state.NewEnvironments(nil)
+	environments.AddEnvironment(env, state.SourceController)
+
+	tests := []struct {
+		name   string
+		envID  string
+		modify func(spec *domain.HTTPRequestSpec)
+		want   []string
+	}{
+		{
+			name:   "valid request",
+			modify: func(spec *domain.HTTPRequestSpec) {},
+		},
+		{
+			name:   "missing method",
+			modify: func(spec *domain.HTTPRequestSpec) { spec.Method = "" },
+			want:   []string{ValidationFieldMethod},
+		},
+		{
+			name:   "missing url",
+			modify: func(spec *domain.HTTPRequestSpec) { spec.URL = " " },
+			want:   []string{ValidationFieldURL},
+		},
+		{
+			name:   "relative url",
+			modify: func(spec *domain.HTTPRequestSpec) { spec.URL = "/users" },
+			want:   []string{ValidationFieldURL},
+		},
+		{
+			name:   "unresolved variable",
+			modify: func(spec *domain.HTTPRequestSpec) { spec.URL = "{{host}}/users" },
+			want:   []string{ValidationFieldURL},
+		},
+		{
+			name:   "variable resolved by the environment",
+			envID:  env.MetaData.ID,
+			modify: func(spec *domain.HTTPRequestSpec) { spec.URL = "{{host}}/users" },
+		},
+		{
+			name: "invalid json body",
+			modify: func(spec *domain.HTTPRequestSpec) {
+				spec.Request.Body = domain.Body{Type: domain.BodyTypeJSON, Data: `{"name":`}
+			},
+			want: []string{ValidationFieldBody},
+		},
+		{
+			name: "existing binary file",
+			modify: func(spec *domain.HTTPRequestSpec) {
+				spec.Request.Body = domain.Body{Type: domain.BodyTypeBinary, BinaryFilePath: existing}
+			},
+		},
+		{
+			name: "missing binary file",
+			modify: func(spec *domain.HTTPRequestSpec) {
+				spec.Request.Body = domain.Body{Type: domain.BodyTypeBinary, BinaryFilePath: existing + ".missing"}
+			},
+			want: []string{ValidationFieldBody},
+		},
+		{
+			name: "missing form file",
+			modify: func(spec *domain.HTTPRequestSpec) {
+				spec.Request.Body = domain.Body{Type: domain.BodyTypeFormData, FormData: domain.FormData{
+					Fields: []domain.FormField{{Type: domain.FormFieldTypeFile, Key: "file", Files: []string{existing, existing + ".missing"}}},
+				}}
+			},
+			want: []string{ValidationFieldBody},
+		},
+		{
+			name:   "missing environment",
+			envID:  "missing",
+			modify: func(spec *domain.HTTPRequestSpec) {},
+			want:   []string{ValidationFieldRequest},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := domain.NewRequest(tt.name)
+			tt.modify(req.Spec.HTTP)
+
+			requests := state.NewRequests(nil)
+			requests.AddRequest(req)
+
+			service := New(requests, environments, state.NewAuthProfiles(nil), nil)
+			issues := service.ValidateRequest(req.MetaData.ID, tt.envID)
+
+			if len(issues) != len(tt.want) {
+				t.Fatalf("expected issues for %v, got %v", tt.want, issues)
+			}
+
+			for i, field := range tt.want {
+				if issues[i].Field != field {
+					t.Errorf("issue %d: expected field %s, got %s", i, field, issues[i].Field)
+				}
+			}
+		})
+	}
+}
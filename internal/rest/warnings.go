@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// warnings checks the request body size and the latency against the thresholds in the preferences.
+// They are only advisory, the request has been sent either way.
+func (s *Service) warnings(requestBytes int, latency time.Duration) []string {
+	if s.preferences == nil {
+		return nil
+	}
+
+	var out []string
+	if limit := s.preferences.Spec.WarnRequestBytes; limit > 0 && requestBytes > limit {
+		out = append(out, fmt.Sprintf("request body is %s, over the %s warning threshold",
+			humanize.Bytes(uint64(requestBytes)), humanize.Bytes(uint64(limit))))
+	}
+
+	if limit := time.Duration(s.preferences.Spec.WarnLatencyMs) * time.Millisecond; limit > 0 && latency > limit {
+		out = append(out, fmt.Sprintf("response took %s, over the %s warning threshold",
+			latency.Round(time.Millisecond), limit))
+	}
+
+	return out
+}
@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+func TestService_SendRequest_Warnings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("large")
+	req.Spec.HTTP.Method = http.MethodPost
+	req.Spec.HTTP.URL = server.URL
+	req.Spec.HTTP.Request.Body = domain.Body{Type: domain.BodyTypeText, Data: strings.Repeat("a", 2048)}
+	requests.AddRequest(req)
+
+	preferences := domain.NewPreferences()
+	service := New(requests, state.NewEnvironments(nil), state.NewAuthProfiles(nil), preferences)
+
+	res, err := service.SendRequest(req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if len(res.Warnings) != 0 {
+		t.Errorf("expected no warnings without thresholds, got %v", res.Warnings)
+	}
+
+	preferences.Spec.WarnRequestBytes = 1024
+	res, err = service.SendRequest(req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if len(res.Warnings) != 1 || !strings.Contains(res.Warnings[0], "request body") {
+		t.Errorf("expected a request size warning, got %v", res.Warnings)
+	}
+}
+
+func TestService_warnings_Latency(t *testing.T) {
+	preferences := domain.NewPreferences()
+	preferences.Spec.WarnLatencyMs = 100
+	service := New(state.NewRequests(nil), state.NewEnvironments(nil), state.NewAuthProfiles(nil), preferences)
+
+	if got := service.warnings(0, 50*time.Millisecond); len(got) != 0 {
+		t.Errorf("expected no warnings under the threshold, got %v", got)
+	}
+
+	if got := service.warnings(0, 150*time.Millisecond); len(got) != 1 || !strings.Contains(got[0], "response took") {
+		t.Errorf("expected a latency warning, got %v", got)
+	}
+}
@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const defaultWireCaptureFile = "chapar-wire.log"
+
+// wireCaptureFile returns the file the raw requests and responses are appended to,
+// or an empty string when the capture is disabled in the preferences.
+func (s *Service) wireCaptureFile() string {
+	if s.preferences == nil || !s.preferences.Spec.DebugWireCapture {
+		return ""
+	}
+
+	if s.preferences.Spec.WireCaptureFile != "" {
+		return s.preferences.Spec.WireCaptureFile
+	}
+
+	return filepath.Join(os.TempDir(), defaultWireCaptureFile)
+}
+
+// dumpRequest returns the request as it goes on the wire when the capture is enabled.
+// It has to run before the request is sent, as sending consumes the body.
+func (s *Service) dumpRequest(req *http.Request) []byte {
+	if s.wireCaptureFile() == "" {
+		return nil
+	}
+
+	raw, err := httputil.DumpRequestOut(req, true)
+	if err != nil {
+		fmt.Println("failed to dump request", err)
+		return nil
+	}
+
+	return raw
+}
+
+// captureWire appends the raw request and response to the wire capture file.
+// Failing to capture does not fail the request.
+func (s *Service) captureWire(rawRequest []byte, res *http.Response, body []byte) {
+	name := s.wireCaptureFile()
+	if name == "" {
+		return
+	}
+
+	rawResponse, err := httputil.DumpResponse(res, false)
+	if err != nil {
+		fmt.Println("failed to dump response", err)
+		return
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- request %s ---\n", time.Now().Format(time.RFC3339Nano))
+	buf.Write(rawRequest)
+	buf.WriteString("\n--- response ---\n")
+	buf.Write(rawResponse)
+	buf.Write(body)
+	buf.WriteString("\n\n")
+
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		fmt.Println("failed to open wire capture file", err)
+		return
+	}
+	defer f.Close()
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		fmt.Println("failed to write wire capture", err)
+	}
+}
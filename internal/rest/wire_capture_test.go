@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+func TestService_SendRequest_WireCapture(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"created"}`))
+	}))
+	defer server.Close()
+
+	requests := state.NewRequests(nil)
+	req := domain.NewRequest("capture")
+	req.Spec.HTTP.Method = http.MethodPost
+	req.Spec.HTTP.URL = server.URL + "/users"
+	req.Spec.HTTP.Request.Body = domain.Body{Type: domain.BodyTypeText, Data: "name=chapar"}
+	requests.AddRequest(req)
+
+	captureFile := filepath.Join(t.TempDir(), "wire.log")
+	preferences := domain.NewPreferences()
+	preferences.Spec.WireCaptureFile = captureFile
+	service := New(requests, state.NewEnvironments(nil), state.NewAuthProfiles(nil), preferences)
+
+	if _, err := service.SendRequest(req.MetaData.ID, ""); err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if _, err := os.Stat(captureFile); !os.IsNotExist(err) {
+		t.Fatalf("expected no capture without the debug flag, got %v", err)
+	}
+
+	preferences.Spec.DebugWireCapture = true
+	res, err := service.SendRequest(req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	if string(res.Body) != `{"status":"created"}` {
+		t.Errorf("expected the response body to be intact, got %s", res.Body)
+	}
+
+	data, err := os.ReadFile(captureFile)
+	if err != nil {
+		t.Fatalf("failed to read capture file: %v", err)
+	}
+
+	for _, want := range []string{"POST /users HTTP/1.1", "name=chapar", "HTTP/1.1 200 OK", `{"status":"created"}`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected the capture to contain %q, got:\n%s", want, data)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package state
+
+import (
+	"sort"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/repository"
+	"github.com/chapar-rest/chapar/internal/safemap"
+)
+
+type AuthProfileChangeListener func(profile *domain.AuthProfile, action Action)
+
+type AuthProfiles struct {
+	changeListeners []AuthProfileChangeListener
+
+	profiles *safemap.Map[*domain.AuthProfile]
+
+	repository repository.Repository
+}
+
+func NewAuthProfiles(repository repository.Repository) *AuthProfiles {
+	return &AuthProfiles{
+		repository: repository,
+		profiles:   safemap.New[*domain.AuthProfile](),
+	}
+}
+
+func (m *AuthProfiles) AddAuthProfileChangeListener(listener AuthProfileChangeListener) {
+	m.changeListeners = append(m.changeListeners, listener)
+}
+
+func (m *AuthProfiles) notifyChange(profile *domain.AuthProfile, action Action) {
+	for _, listener := range m.changeListeners {
+		listener(profile, action)
+	}
+}
+
+func (m *AuthProfiles) AddAuthProfile(profile *domain.AuthProfile) {
+	m.profiles.Set(profile.MetaData.ID, profile)
+	m.notifyChange(profile, ActionAdd)
+}
+
+func (m *AuthProfiles) GetAuthProfile(id string) *domain.AuthProfile {
+	profile, _ := m.profiles.Get(id)
+	return profile
+}
+
+// GetAuthProfiles returns the auth profiles sorted by name.
+func (m *AuthProfiles) GetAuthProfiles() []*domain.AuthProfile {
+	profiles := m.profiles.Values()
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].MetaData.Name < profiles[j].MetaData.Name
+	})
+	return profiles
+}
+
+func (m *AuthProfiles) UpdateAuthProfile(profile *domain.AuthProfile, stateOnly bool) error {
+	if _, ok := m.profiles.Get(profile.MetaData.ID); !ok {
+		return ErrNotFound
+	}
+
+	if !stateOnly {
+		if err := m.repository.UpdateAuthProfile(profile); err != nil {
+			return err
+		}
+	}
+
+	m.profiles.Set(profile.MetaData.ID, profile)
+	m.notifyChange(profile, ActionUpdate)
+	return nil
+}
+
+func (m *AuthProfiles) RemoveAuthProfile(profile *domain.AuthProfile, stateOnly bool) error {
+	if _, ok := m.profiles.Get(profile.MetaData.ID); !ok {
+		return ErrNotFound
+	}
+
+	if !stateOnly {
+		if err := m.repository.DeleteAuthProfile(profile); err != nil {
+			return err
+		}
+	}
+
+	m.profiles.Delete(profile.MetaData.ID)
+	m.notifyChange(profile, ActionDelete)
+	return nil
+}
+
+func (m *AuthProfiles) LoadAuthProfilesFromDisk() ([]*domain.AuthProfile, error) {
+	profiles, err := m.repository.LoadAuthProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range profiles {
+		m.profiles.Set(profile.MetaData.ID, profile)
+	}
+
+	return profiles, nil
+}
@@ -0,0 +1,45 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+func TestAuthProfiles_ChangeListener(t *testing.T) {
+	m := NewAuthProfiles(nil)
+
+	actions := make([]Action, 0)
+	m.AddAuthProfileChangeListener(func(_ *domain.AuthProfile, action Action) {
+		actions = append(actions, action)
+	})
+
+	staging := domain.NewAuthProfile("staging")
+	m.AddAuthProfile(staging)
+	m.AddAuthProfile(domain.NewAuthProfile("production"))
+
+	if err := m.UpdateAuthProfile(staging, true); err != nil {
+		t.Fatalf("failed to update profile: %v", err)
+	}
+
+	if err := m.RemoveAuthProfile(staging, true); err != nil {
+		t.Fatalf("failed to remove profile: %v", err)
+	}
+
+	want := []Action{ActionAdd, ActionAdd, ActionUpdate, ActionDelete}
+	if len(actions) != len(want) {
+		t.Fatalf("expected actions %v, got %v", want, actions)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Errorf("expected actions %v, got %v", want, actions)
+			break
+		}
+	}
+
+	m.AddAuthProfile(domain.NewAuthProfile("dev"))
+	profiles := m.GetAuthProfiles()
+	if len(profiles) != 2 || profiles[0].MetaData.Name != "dev" || profiles[1].MetaData.Name != "production" {
+		t.Errorf("expected profiles sorted by name")
+	}
+}
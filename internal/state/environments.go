@@ -36,6 +36,19 @@ func (m *Environments) AddActiveEnvironmentChangeListener(listener ActiveEnviron
 	m.activeEnvironmentChangeListeners = append(m.activeEnvironmentChangeListeners, listener)
 }
 
+// OnActiveChanged registers f to be called with the id of the active environment when another one
+// is activated, when the active one is updated and when it is cleared, with an empty id, so callers
+// can invalidate state derived from it.
+func (m *Environments) OnActiveChanged(f func(id string)) {
+	m.AddActiveEnvironmentChangeListener(func(env *domain.Environment) {
+		if env == nil {
+			f("")
+			return
+		}
+		f(env.MetaData.ID)
+	})
+}
+
 func (m *Environments) notifyEnvironmentChange(environment *domain.Environment, source Source, action Action) {
 	for _, listener := range m.environmentChangeListeners {
 		listener(environment, source, action)
@@ -66,6 +79,11 @@ func (m *Environments) RemoveEnvironment(environment *domain.Environment, source
 
 	m.environments.Delete(environment.MetaData.ID)
 	m.notifyEnvironmentChange(environment, source, ActionDelete)
+
+	// a removed environment can not stay active
+	if m.isActive(environment.MetaData.ID) {
+		m.ClearActiveEnvironment()
+	}
 	return nil
 }
 
@@ -87,11 +105,16 @@ func (m *Environments) UpdateEnvironment(env *domain.Environment, source Source,
 
 	m.environments.Set(env.MetaData.ID, env)
 	m.notifyEnvironmentChange(env, source, ActionUpdate)
+	m.refreshActiveEnvironment(env)
 
 	return nil
 }
 
 func (m *Environments) SetActiveEnvironment(environment *domain.Environment) {
+	if environment == nil {
+		return
+	}
+
 	if _, ok := m.environments.Get(environment.MetaData.ID); !ok {
 		return
 	}
@@ -109,6 +132,19 @@ func (m *Environments) GetActiveEnvironment() *domain.Environment {
 	return m.activeEnvironment
 }
 
+func (m *Environments) isActive(id string) bool {
+	return m.activeEnvironment != nil && m.activeEnvironment.MetaData.ID == id
+}
+
+// refreshActiveEnvironment replaces the active environment with its updated version,
+// so listeners holding derived state such as resolved variables can invalidate it.
+func (m *Environments) refreshActiveEnvironment(env *domain.Environment) {
+	if m.isActive(env.MetaData.ID) {
+		m.activeEnvironment = env
+		m.notifyActiveEnvironmentChange(env)
+	}
+}
+
 func (m *Environments) GetEnvironmentFromDisc(id string) (*domain.Environment, error) {
 	env, ok := m.environments.Get(id)
 	if !ok {
@@ -132,6 +168,7 @@ func (m *Environments) ReloadEnvironmentFromDisc(id string, source Source) {
 
 	m.environments.Set(id, env)
 	m.notifyEnvironmentChange(env, source, ActionUpdate)
+	m.refreshActiveEnvironment(env)
 }
 
 func (m *Environments) GetEnvironments() []*domain.Environment {
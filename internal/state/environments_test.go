@@ -0,0 +1,148 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+func TestEnvironments_ActiveEnvironmentChangeListener(t *testing.T) {
+	m := NewEnvironments(nil)
+
+	dev := domain.NewEnvironment("dev")
+	prod := domain.NewEnvironment("prod")
+	m.AddEnvironment(dev, SourceController)
+	m.AddEnvironment(prod, SourceController)
+
+	var got []string
+	m.AddActiveEnvironmentChangeListener(func(env *domain.Environment) {
+		if env == nil {
+			got = append(got, "")
+			return
+		}
+		got = append(got, env.MetaData.Name)
+	})
+
+	m.SetActiveEnvironment(dev)
+	m.SetActiveEnvironment(prod)
+	// unknown environments are ignored
+	m.SetActiveEnvironment(domain.NewEnvironment("unknown"))
+	m.SetActiveEnvironment(nil)
+	m.ClearActiveEnvironment()
+
+	want := []string{"dev", "prod", ""}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d notifications, got %v", len(want), got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("notification %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestEnvironments_UpdateActiveEnvironment(t *testing.T) {
+	m := NewEnvironments(nil)
+
+	env := domain.NewEnvironment("dev")
+	m.AddEnvironment(env, SourceController)
+	m.SetActiveEnvironment(env)
+
+	var notified *domain.Environment
+	m.AddActiveEnvironmentChangeListener(func(env *domain.Environment) {
+		notified = env
+	})
+
+	updated := env.Clone()
+	updated.MetaData.ID = env.MetaData.ID
+	updated.Spec.Values = []domain.KeyValue{{Key: "host", Value: "localhost", Enable: true}}
+	if err := m.UpdateEnvironment(updated, SourceController, true); err != nil {
+		t.Fatalf("failed to update environment: %v", err)
+	}
+
+	if m.GetActiveEnvironment() != updated || notified != updated {
+		t.Errorf("expected the updated environment to become the active one")
+	}
+
+	// updating another environment leaves the active one alone
+	other := domain.NewEnvironment("prod")
+	m.AddEnvironment(other, SourceController)
+	notified = nil
+	if err := m.UpdateEnvironment(other, SourceController, true); err != nil {
+		t.Fatalf("failed to update environment: %v", err)
+	}
+
+	if notified != nil {
+		t.Errorf("expected no notification for an inactive environment")
+	}
+}
+
+func TestEnvironments_RemoveActiveEnvironment(t *testing.T) {
+	m := NewEnvironments(nil)
+
+	env := domain.NewEnvironment("dev")
+	m.AddEnvironment(env, SourceController)
+	m.SetActiveEnvironment(env)
+
+	notified := false
+	m.AddActiveEnvironmentChangeListener(func(env *domain.Environment) {
+		notified = env == nil
+	})
+
+	if err := m.RemoveEnvironment(env, SourceController, true); err != nil {
+		t.Fatalf("failed to remove environment: %v", err)
+	}
+
+	if m.GetActiveEnvironment() != nil || !notified {
+		t.Errorf("expected the removed environment to be cleared as the active one")
+	}
+}
+
+func TestEnvironments_OnActiveChanged(t *testing.T) {
+	m := NewEnvironments(nil)
+
+	dev := domain.NewEnvironment("dev")
+	prod := domain.NewEnvironment("prod")
+	m.AddEnvironment(dev, SourceController)
+	m.AddEnvironment(prod, SourceController)
+
+	var got []string
+	m.OnActiveChanged(func(id string) {
+		got = append(got, id)
+	})
+
+	m.SetActiveEnvironment(dev)
+
+	// updating the active environment notifies with the refreshed one
+	updated := dev.Clone()
+	updated.MetaData.ID = dev.MetaData.ID
+	if err := m.UpdateEnvironment(updated, SourceController, true); err != nil {
+		t.Fatalf("failed to update environment: %v", err)
+	}
+
+	if m.GetActiveEnvironment() != updated {
+		t.Errorf("expected the refreshed environment to be the active one")
+	}
+
+	// updating an inactive environment does not notify
+	if err := m.UpdateEnvironment(prod, SourceController, true); err != nil {
+		t.Fatalf("failed to update environment: %v", err)
+	}
+
+	// removing the active environment clears it
+	if err := m.RemoveEnvironment(updated, SourceController, true); err != nil {
+		t.Fatalf("failed to remove environment: %v", err)
+	}
+
+	want := []string{dev.MetaData.ID, dev.MetaData.ID, ""}
+	if len(got) != len(want) {
+		t.Fatalf("expected notifications %v, got %v", want, got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("notification %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
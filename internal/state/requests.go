@@ -2,6 +2,8 @@ package state
 
 import (
 	"path"
+	"slices"
+	"sort"
 
 	"github.com/chapar-rest/chapar/internal/domain"
 	"github.com/chapar-rest/chapar/internal/repository"
@@ -72,6 +74,44 @@ func (m *Requests) RemoveRequest(request *domain.Request, stateOnly bool) error
 	return nil
 }
 
+// Duplicate makes a deep copy of the persisted version of the request with a new id,
+// saves it next to the original and adds it to the state.
+func (m *Requests) Duplicate(id string) (*domain.Request, error) {
+	// read request from file to make sure we have the latest persisted data
+	req, err := m.GetRequestFromDisc(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// get a free file path next to the original, so duplicating twice does not overwrite the first copy
+	var filePath *repository.FilePath
+	if col := m.GetCollection(req.CollectionID); col != nil {
+		filePath, err = m.repository.GetCollectionRequestNewFilePath(col, req.MetaData.Name+" (copy)")
+	} else {
+		filePath, err = m.repository.GetNewRequestFilePath(req.MetaData.Name + " (copy)")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	clone := req.Clone()
+	clone.MetaData.Name = filePath.NewName
+	clone.FilePath = filePath.Path
+
+	if err := m.repository.UpdateRequest(clone); err != nil {
+		return nil, err
+	}
+
+	if clone.CollectionID != "" {
+		if col := m.GetCollection(clone.CollectionID); col != nil {
+			m.AddRequestToCollection(col, clone)
+		}
+	}
+
+	m.AddRequest(clone)
+	return clone, nil
+}
+
 func (m *Requests) AddCollection(collection *domain.Collection) {
 	m.collections.Set(collection.MetaData.ID, collection)
 	m.notifyCollectionChange(collection, ActionAdd)
@@ -155,6 +195,122 @@ func (m *Requests) UpdateCollection(collection *domain.Collection, stateOnly boo
 	return nil
 }
 
+// Reorder moves the request or collection with the given id to newIndex among its
+// siblings and persists the new order. Requests are ordered within their collection,
+// standalone requests and collections are ordered at the top level.
+func (m *Requests) Reorder(id string, newIndex int) error {
+	if req, ok := m.requests.Get(id); ok {
+		siblings := m.siblings(req.CollectionID)
+		siblings = moveTo(siblings, func(r *domain.Request) bool { return r.MetaData.ID == id }, newIndex)
+
+		for i, r := range siblings {
+			if err := m.persistRequestOrder(r, i); err != nil {
+				return err
+			}
+		}
+
+		if col := m.GetCollection(req.CollectionID); col != nil {
+			col.Spec.Requests = siblings
+		}
+
+		m.notifyRequestChange(req, ActionUpdate)
+		return nil
+	}
+
+	if col, ok := m.collections.Get(id); ok {
+		cols := m.GetCollections()
+		domain.SortCollections(cols)
+		cols = moveTo(cols, func(c *domain.Collection) bool { return c.MetaData.ID == id }, newIndex)
+
+		for i, c := range cols {
+			if err := m.persistCollectionOrder(c, i); err != nil {
+				return err
+			}
+		}
+
+		m.notifyCollectionChange(col, ActionUpdate)
+		return nil
+	}
+
+	return ErrNotFound
+}
+
+// IndexOf returns the position of the request or collection with the given id
+// among its siblings in the sidebar, or -1 if it is not found.
+func (m *Requests) IndexOf(id string) int {
+	if req, ok := m.requests.Get(id); ok {
+		return slices.IndexFunc(m.siblings(req.CollectionID), func(r *domain.Request) bool { return r.MetaData.ID == id })
+	}
+
+	cols := m.GetCollections()
+	domain.SortCollections(cols)
+	return slices.IndexFunc(cols, func(c *domain.Collection) bool { return c.MetaData.ID == id })
+}
+
+// persistRequestOrder stores the order on the persisted version of the request,
+// so unsaved changes of the request are not written along with it.
+func (m *Requests) persistRequestOrder(req *domain.Request, order int) error {
+	fresh, err := m.repository.GetRequest(req.FilePath)
+	if err != nil {
+		return err
+	}
+
+	fresh.FilePath = req.FilePath
+	fresh.MetaData.Order = order
+	if err := m.repository.UpdateRequest(fresh); err != nil {
+		return err
+	}
+
+	req.MetaData.Order = order
+	req.FilePath = fresh.FilePath
+	return nil
+}
+
+func (m *Requests) persistCollectionOrder(col *domain.Collection, order int) error {
+	fresh, err := repository.LoadFromYaml[domain.Collection](col.FilePath)
+	if err != nil {
+		return err
+	}
+
+	fresh.FilePath = col.FilePath
+	fresh.Spec.Order = order
+	if err := m.repository.UpdateCollection(fresh); err != nil {
+		return err
+	}
+
+	col.Spec.Order = order
+	col.FilePath = fresh.FilePath
+	return nil
+}
+
+// siblings returns the requests of the collection with the given id, or the
+// standalone requests when collectionID is empty, sorted by their sidebar order.
+func (m *Requests) siblings(collectionID string) []*domain.Request {
+	out := make([]*domain.Request, 0)
+	for _, r := range m.requests.Values() {
+		if r.CollectionID == collectionID {
+			out = append(out, r)
+		}
+	}
+
+	domain.SortRequests(out)
+	return out
+}
+
+// moveTo returns items with the first item matching fn moved to index,
+// clamping index to the bounds of the slice.
+func moveTo[T any](items []T, fn func(T) bool, index int) []T {
+	from := slices.IndexFunc(items, fn)
+	if from < 0 {
+		return items
+	}
+
+	item := items[from]
+	items = slices.Delete(items, from, from+1)
+	index = max(0, min(index, len(items)))
+	return slices.Insert(items, index, item)
+}
+
 func fixRequestFilePath(request *domain.Request, collection *domain.Collection) string {
 	collectionDir, _ := path.Split(collection.FilePath)
 	requestFileName := path.Base(request.FilePath)
@@ -165,6 +321,21 @@ func (m *Requests) GetRequests() []*domain.Request {
 	return m.requests.Values()
 }
 
+// ByTag returns the requests tagged with tag, sorted by name.
+func (m *Requests) ByTag(tag string) []*domain.Request {
+	out := make([]*domain.Request, 0)
+	for _, req := range m.requests.Values() {
+		if req.HasTag(tag) {
+			out = append(out, req)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].MetaData.Name < out[j].MetaData.Name
+	})
+	return out
+}
+
 func (m *Requests) GetCollections() []*domain.Collection {
 	return m.collections.Values()
 }
@@ -0,0 +1,202 @@
+package state
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/repository"
+)
+
+func newTestRequests(t *testing.T) *Requests {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	return NewRequests(&repository.Filesystem{})
+}
+
+func addTestRequest(t *testing.T, m *Requests, name string) *domain.Request {
+	t.Helper()
+
+	req := domain.NewRequest(name)
+	if err := m.repository.UpdateRequest(req); err != nil {
+		t.Fatalf("failed to save request: %v", err)
+	}
+	m.AddRequest(req)
+	return req
+}
+
+func TestRequests_Duplicate(t *testing.T) {
+	m := newTestRequests(t)
+	req := addTestRequest(t, m, "original")
+
+	clone, err := m.Duplicate(req.MetaData.ID)
+	if err != nil {
+		t.Fatalf("failed to duplicate request: %v", err)
+	}
+
+	if clone.MetaData.ID == req.MetaData.ID {
+		t.Errorf("expected a new id for the clone")
+	}
+
+	if clone.MetaData.Name != "original (copy)" {
+		t.Errorf("expected name %q, got %q", "original (copy)", clone.MetaData.Name)
+	}
+
+	if m.GetRequest(clone.MetaData.ID) == nil {
+		t.Errorf("expected clone to be added to the state")
+	}
+
+	if _, err := m.GetRequestFromDisc(clone.MetaData.ID); err != nil {
+		t.Errorf("expected clone to be persisted, got %v", err)
+	}
+
+	// mutating the clone must not change the original
+	clone.Spec.HTTP.Request.Headers[0].Value = "text/plain"
+	clone.Spec.HTTP.Request.Headers = append(clone.Spec.HTTP.Request.Headers, domain.KeyValue{Key: "X-Test"})
+
+	if got := req.Spec.HTTP.Request.Headers[0].Value; got != "application/json" {
+		t.Errorf("original header changed to %q", got)
+	}
+
+	if len(req.Spec.HTTP.Request.Headers) != 1 {
+		t.Errorf("expected original to keep 1 header, got %d", len(req.Spec.HTTP.Request.Headers))
+	}
+}
+
+func TestRequests_Duplicate_Twice(t *testing.T) {
+	m := newTestRequests(t)
+	req := addTestRequest(t, m, "original")
+
+	first, err := m.Duplicate(req.MetaData.ID)
+	if err != nil {
+		t.Fatalf("failed to duplicate request: %v", err)
+	}
+
+	second, err := m.Duplicate(req.MetaData.ID)
+	if err != nil {
+		t.Fatalf("failed to duplicate request: %v", err)
+	}
+
+	if first.FilePath == second.FilePath {
+		t.Fatalf("expected the copies to have their own files, both use %s", first.FilePath)
+	}
+
+	for _, clone := range []*domain.Request{first, second} {
+		loaded, err := m.GetRequestFromDisc(clone.MetaData.ID)
+		if err != nil {
+			t.Fatalf("expected %s to be persisted, got %v", clone.FilePath, err)
+		}
+
+		if loaded.MetaData.ID != clone.MetaData.ID {
+			t.Errorf("expected %s to hold request %s, got %s", clone.FilePath, clone.MetaData.ID, loaded.MetaData.ID)
+		}
+	}
+}
+
+func TestRequests_Duplicate_NotFound(t *testing.T) {
+	m := newTestRequests(t)
+	if _, err := m.Duplicate("missing"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRequests_Description_RoundTrip(t *testing.T) {
+	m := newTestRequests(t)
+	req := addTestRequest(t, m, "documented")
+
+	req.MetaData.Description = "Checks the health endpoint.\nUsed by the deploy pipeline."
+	if err := m.UpdateRequest(req, false); err != nil {
+		t.Fatalf("failed to update request: %v", err)
+	}
+
+	loaded, err := m.GetRequestFromDisc(req.MetaData.ID)
+	if err != nil {
+		t.Fatalf("failed to load request: %v", err)
+	}
+
+	if loaded.MetaData.Description != req.MetaData.Description {
+		t.Errorf("expected description %q, got %q", req.MetaData.Description, loaded.MetaData.Description)
+	}
+}
+
+func TestRequests_Color_RoundTrip(t *testing.T) {
+	m := newTestRequests(t)
+	req := addTestRequest(t, m, "colored")
+
+	req.MetaData.Color = "#e53935"
+	if err := m.UpdateRequest(req, false); err != nil {
+		t.Fatalf("failed to update request: %v", err)
+	}
+
+	loaded, err := m.GetRequestFromDisc(req.MetaData.ID)
+	if err != nil {
+		t.Fatalf("failed to load request: %v", err)
+	}
+
+	if loaded.MetaData.Color != req.MetaData.Color {
+		t.Errorf("expected color %q, got %q", req.MetaData.Color, loaded.MetaData.Color)
+	}
+
+	if !domain.CompareRequests(loaded, req) {
+		t.Errorf("expected the loaded request to be equal to the saved one")
+	}
+}
+
+func TestRequests_ByTag(t *testing.T) {
+	m := newTestRequests(t)
+	b := addTestRequest(t, m, "b")
+	a := addTestRequest(t, m, "a")
+	c := addTestRequest(t, m, "c")
+
+	b.MetaData.Tags = []string{"smoke"}
+	a.MetaData.Tags = []string{"Smoke", "wip"}
+	c.MetaData.Tags = []string{"regression"}
+
+	got := m.ByTag("smoke")
+	if len(got) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(got))
+	}
+
+	if got[0].MetaData.ID != a.MetaData.ID || got[1].MetaData.ID != b.MetaData.ID {
+		t.Errorf("expected requests sorted by name, got %s, %s", got[0].MetaData.Name, got[1].MetaData.Name)
+	}
+
+	if got := m.ByTag("missing"); len(got) != 0 {
+		t.Errorf("expected no requests, got %d", len(got))
+	}
+}
+
+func requestNames(requests []*domain.Request) []string {
+	out := make([]string, 0, len(requests))
+	for _, r := range requests {
+		out = append(out, r.MetaData.Name)
+	}
+	return out
+}
+
+func TestRequests_Reorder(t *testing.T) {
+	m := newTestRequests(t)
+	addTestRequest(t, m, "a")
+	addTestRequest(t, m, "b")
+	c := addTestRequest(t, m, "c")
+
+	if err := m.Reorder(c.MetaData.ID, 0); err != nil {
+		t.Fatalf("failed to reorder request: %v", err)
+	}
+
+	// load the requests from disk to make sure the order is persisted
+	reloaded := NewRequests(&repository.Filesystem{})
+	reqs, err := reloaded.LoadRequestsFromDisk()
+	if err != nil {
+		t.Fatalf("failed to load requests: %v", err)
+	}
+
+	domain.SortRequests(reqs)
+	if got := requestNames(reqs); !slices.Equal(got, []string{"c", "a", "b"}) {
+		t.Errorf("expected order [c a b], got %v", got)
+	}
+
+	if err := m.Reorder("missing", 0); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
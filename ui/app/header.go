@@ -1,11 +1,14 @@
 package app
 
 import (
+	"sort"
+
 	"gioui.org/layout"
 	"gioui.org/unit"
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/palette"
 	"github.com/chapar-rest/chapar/internal/state"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 	"github.com/chapar-rest/chapar/ui/widgets"
@@ -23,8 +26,12 @@ type Header struct {
 	iconDarkMode  material.LabelStyle
 	iconLightMode material.LabelStyle
 
+	verticalLayout     bool
+	layoutSwitchButton *widgets.IconButton
+
 	OnSelectedEnvChanged func(env *domain.Environment)
 	OnThemeSwitched      func(isLight bool)
+	OnLayoutSwitched     func(isVertical bool)
 }
 
 const (
@@ -45,6 +52,18 @@ func NewHeader(envState *state.Environments, theme *chapartheme.Theme) *Header {
 	h.themeSwitcher = material.Switch(theme.Material(), h.switchState, "")
 	h.envDropDown = widgets.NewDropDown(theme)
 	h.envDropDown.MinWidth = unit.Dp(150)
+
+	h.layoutSwitchButton = &widgets.IconButton{
+		Icon:      widgets.SwapHoriz,
+		Size:      unit.Dp(24),
+		Clickable: new(widget.Clickable),
+		OnClick: func() {
+			h.SetVerticalLayout(!h.verticalLayout)
+			if h.OnLayoutSwitched != nil {
+				h.OnLayoutSwitched(h.verticalLayout)
+			}
+		},
+	}
 	return h
 }
 
@@ -73,10 +92,48 @@ func (h *Header) SetSelectedEnvironment(env *domain.Environment) {
 	h.envDropDown.SetSelectedByTitle(env.MetaData.Name)
 }
 
+// ClearSelectedEnvironment selects no environment without clearing the active one again.
+func (h *Header) ClearSelectedEnvironment() {
+	h.selectedEnv = none
+	h.envDropDown.SetSelectedByTitle(noEnvironment)
+}
+
+// EnvironmentCommands returns the command palette commands switching the selected environment.
+// They go through the drop down so switching is handled the same way as selecting it there.
+func (h *Header) EnvironmentCommands() []palette.Command {
+	commands := []palette.Command{
+		{Title: "Switch environment: " + noEnvironment, Run: func() { h.envDropDown.SetSelectedByTitle(noEnvironment) }},
+	}
+
+	envs := h.envState.GetEnvironments()
+	sort.Slice(envs, func(i, j int) bool {
+		return envs[i].MetaData.Name < envs[j].MetaData.Name
+	})
+
+	for _, env := range envs {
+		name := env.MetaData.Name
+		commands = append(commands, palette.Command{
+			Title: "Switch environment: " + name,
+			Run:   func() { h.envDropDown.SetSelectedByTitle(name) },
+		})
+	}
+
+	return commands
+}
+
 func (h *Header) SetTheme(isDark bool) {
 	h.switchState.Value = !isDark
 }
 
+func (h *Header) SetVerticalLayout(isVertical bool) {
+	h.verticalLayout = isVertical
+	if isVertical {
+		h.layoutSwitchButton.Icon = widgets.SwapVert
+	} else {
+		h.layoutSwitchButton.Icon = widgets.SwapHoriz
+	}
+}
+
 func (h *Header) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
 	inset := layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4), Left: unit.Dp(4)}
 
@@ -110,6 +167,12 @@ func (h *Header) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dim
 				}),
 				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 					return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							return layout.Inset{Right: unit.Dp(20)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+								h.layoutSwitchButton.Color = theme.TextColor
+								return h.layoutSwitchButton.Layout(gtx, theme)
+							})
+						}),
 						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 							h.iconDarkMode.Color = theme.TextColor
 							return h.iconDarkMode.Layout(gtx)
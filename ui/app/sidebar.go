@@ -86,6 +86,10 @@ func (s *Sidebar) SelectedIndex() int {
 	return s.selectedIndex
 }
 
+func (s *Sidebar) SetSelectedIndex(index int) {
+	s.selectedIndex = index
+}
+
 func (s *Sidebar) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
 	for i, c := range s.clickables {
 		for c.Clicked(gtx) {
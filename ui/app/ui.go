@@ -15,12 +15,14 @@ import (
 	"gioui.org/widget/material"
 	"github.com/chapar-rest/chapar/internal/domain"
 	"github.com/chapar-rest/chapar/internal/notify"
+	"github.com/chapar-rest/chapar/internal/palette"
 	"github.com/chapar-rest/chapar/internal/repository"
 	"github.com/chapar-rest/chapar/internal/rest"
 	"github.com/chapar-rest/chapar/internal/state"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 	"github.com/chapar-rest/chapar/ui/explorer"
 	"github.com/chapar-rest/chapar/ui/fonts"
+	"github.com/chapar-rest/chapar/ui/keys"
 	"github.com/chapar-rest/chapar/ui/pages/console"
 	"github.com/chapar-rest/chapar/ui/pages/environments"
 	"github.com/chapar-rest/chapar/ui/pages/requests"
@@ -34,8 +36,9 @@ type UI struct {
 	sideBar *Sidebar
 	header  *Header
 
-	consolePage  *console.Console
-	notification *widgets.Notification
+	consolePage    *console.Console
+	notification   *widgets.Notification
+	commandPalette *widgets.CommandPalette
 
 	environmentsView *environments.View
 	requestsView     *requests.View
@@ -57,6 +60,7 @@ func New(w *app.Window) (*UI, error) {
 	repo := &repository.Filesystem{}
 	environmentsState := state.NewEnvironments(repo)
 	requestsState := state.NewRequests(repo)
+	authProfilesState := state.NewAuthProfiles(repo)
 
 	preferences, err := repo.ReadPreferencesData()
 	if err != nil {
@@ -70,7 +74,13 @@ func New(w *app.Window) (*UI, error) {
 		}
 	}
 
-	restService := rest.New(requestsState, environmentsState)
+	if _, err := authProfilesState.LoadAuthProfilesFromDisk(); err != nil {
+		return nil, err
+	}
+
+	// the rest service gets its own copy, as the ui changes its preferences while requests are in flight
+	restPreferences := *preferences
+	restService := rest.New(requestsState, environmentsState, authProfilesState, &restPreferences)
 	explorerController := explorer.NewExplorer(w)
 
 	theme := material.NewTheme()
@@ -83,6 +93,10 @@ func New(w *app.Window) (*UI, error) {
 	u.sideBar = NewSidebar(u.Theme)
 	//
 	u.environmentsView = environments.NewView(u.Theme)
+	u.environmentsView.SetAuthProfiles(authProfilesState.GetAuthProfiles())
+	authProfilesState.AddAuthProfileChangeListener(func(_ *domain.AuthProfile, _ state.Action) {
+		u.environmentsView.SetAuthProfiles(authProfilesState.GetAuthProfiles())
+	})
 	envController := environments.NewController(u.environmentsView, repo, environmentsState, explorerController)
 	if err := envController.LoadData(); err != nil {
 		return nil, err
@@ -92,6 +106,12 @@ func New(w *app.Window) (*UI, error) {
 	environmentsState.AddEnvironmentChangeListener(func(environment *domain.Environment, source state.Source, action state.Action) {
 		u.header.LoadEnvs(environmentsState.GetEnvironments())
 	})
+	environmentsState.AddActiveEnvironmentChangeListener(func(env *domain.Environment) {
+		// the active environment is cleared when it gets removed
+		if env == nil {
+			u.header.ClearSelectedEnvironment()
+		}
+	})
 	//
 	if selectedEnv := environmentsState.GetEnvironment(preferences.Spec.SelectedEnvironment.ID); selectedEnv != nil {
 		environmentsState.SetActiveEnvironment(selectedEnv)
@@ -119,15 +139,51 @@ func New(w *app.Window) (*UI, error) {
 	}
 
 	u.requestsView = requests.NewView(w, u.Theme)
-	reqController := requests.NewController(u.requestsView, repo, requestsState, environmentsState, explorerController, restService)
+	u.requestsView.SetRequestSplitAxis(requestSplitAxis(preferences.Spec.VerticalRequestLayout))
+	u.requestsView.SetMaxRenderBytes(preferences.Spec.RenderLimit())
+	u.header.SetVerticalLayout(preferences.Spec.VerticalRequestLayout)
+	u.header.OnLayoutSwitched = func(isVertical bool) {
+		u.requestsView.SetRequestSplitAxis(requestSplitAxis(isVertical))
+
+		preferences.Spec.VerticalRequestLayout = isVertical
+		if err := repo.UpdatePreferences(preferences); err != nil {
+			fmt.Println("failed to update preferences: ", err)
+		}
+	}
+
+	reqController := requests.NewController(u.requestsView, repo, requestsState, environmentsState, authProfilesState, explorerController, restService)
 	if err := reqController.LoadData(); err != nil {
 		return nil, err
 	}
 
 	u.notification = &widgets.Notification{}
+	u.commandPalette = widgets.NewCommandPalette(func() []palette.Command {
+		commands := []palette.Command{
+			{Title: "Go to requests", Run: func() { u.sideBar.SetSelectedIndex(0) }},
+			{Title: "Go to environments", Run: func() { u.sideBar.SetSelectedIndex(1) }},
+		}
+
+		for _, cmd := range reqController.Commands() {
+			run := cmd.Run
+			cmd.Run = func() {
+				u.sideBar.SetSelectedIndex(0)
+				run()
+			}
+			commands = append(commands, cmd)
+		}
+
+		return append(commands, u.header.EnvironmentCommands()...)
+	})
 	return u, nil
 }
 
+func requestSplitAxis(isVertical bool) layout.Axis {
+	if isVertical {
+		return layout.Vertical
+	}
+	return layout.Horizontal
+}
+
 func (u *UI) Run() error {
 	// ops are the operations from the UI
 	var ops op.Ops
@@ -162,6 +218,8 @@ func (u *UI) Layout(gtx layout.Context, windowWidth int) layout.Dimensions {
 	background := macro.Stop()
 
 	background.Add(gtx.Ops)
+	keys.OnCommandPaletteCommand(gtx, u, u.commandPalette.Show)
+
 	layout.Stack{Alignment: layout.S}.Layout(gtx,
 		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
 			gtx.Constraints.Min.X = gtx.Constraints.Max.X
@@ -191,6 +249,9 @@ func (u *UI) Layout(gtx layout.Context, windowWidth int) layout.Dimensions {
 				}),
 			)
 		}),
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return u.commandPalette.Layout(gtx, u.Theme)
+		}),
 		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
 			return notify.NotificationController.Layout(gtx, u.Theme, windowWidth)
 		}),
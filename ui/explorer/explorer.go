@@ -64,6 +64,39 @@ func (e *Explorer) ChoseFile(onResult func(r Result), extensions ...string) {
 	}(onResult)
 }
 
+// SaveFile asks the user where to save data, name being the suggested file name.
+func (e *Explorer) SaveFile(name string, data []byte, onResult func(r Result)) {
+	go func(onResult func(r Result)) {
+		defer func(e *Explorer) {
+			e.w.Invalidate()
+		}(e)
+
+		file, err := e.expl.CreateFile(name)
+		if err != nil {
+			onResult(Result{Error: fmt.Errorf("failed creating file: %w", err)})
+			return
+		}
+
+		filePath := ""
+		// get file path if possible
+		if f, ok := file.(*os.File); ok {
+			filePath = f.Name()
+		}
+
+		if _, err := file.Write(data); err != nil {
+			_ = file.Close()
+			onResult(Result{Error: fmt.Errorf("failed writing file: %w", err), FilePath: filePath})
+			return
+		}
+
+		if err := file.Close(); err != nil {
+			onResult(Result{Error: fmt.Errorf("failed closing file: %w", err), FilePath: filePath})
+			return
+		}
+		onResult(Result{Data: data, FilePath: filePath})
+	}(onResult)
+}
+
 func (e *Explorer) ChoseFiles(onResult func(r []Result), extensions ...string) {
 	go func(onResult func(r []Result)) {
 		defer func(e *Explorer) {
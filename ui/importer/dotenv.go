@@ -0,0 +1,100 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ImportDotEnv parses KEY=value lines of a .env file into an environment named ".env".
+// Blank lines and comments are skipped, an export prefix is allowed and values can be
+// double quoted (with \n, \" and \\ escapes) or single quoted (taken as is).
+// The environment is not saved.
+func ImportDotEnv(r io.Reader) (*domain.Environment, error) {
+	env := domain.NewEnvironment(".env")
+	env.Spec.Values = make([]domain.KeyValue, 0)
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(line, "export "); ok {
+			line = strings.TrimSpace(rest)
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid line %d: expected KEY=value", lineNumber)
+		}
+
+		value, err := parseDotEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid line %d: %w", lineNumber, err)
+		}
+
+		env.Spec.Values = append(env.Spec.Values, domain.KeyValue{
+			ID:     uuid.NewString(),
+			Key:    key,
+			Value:  value,
+			Enable: true,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func parseDotEnvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	switch value[0] {
+	case '\'':
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("missing closing quote")
+		}
+		return value[1 : end+1], nil
+	case '"':
+		var out strings.Builder
+		for i := 1; i < len(value); i++ {
+			switch c := value[i]; {
+			case c == '"':
+				return out.String(), nil
+			case c == '\\' && i+1 < len(value):
+				i++
+				switch value[i] {
+				case 'n':
+					out.WriteByte('\n')
+				case 't':
+					out.WriteByte('\t')
+				default:
+					out.WriteByte(value[i])
+				}
+			default:
+				out.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("missing closing quote")
+	}
+
+	// unquoted values end at an inline comment
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value), nil
+}
@@ -0,0 +1,66 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImportDotEnv(t *testing.T) {
+	data := `# database settings
+DB_HOST=localhost
+export DB_PORT=5432
+
+DB_USER = admin # inline comment
+DB_PASSWORD="p@ss # not a comment"
+GREETING="hello\nworld \"quoted\""
+RAW='single $quoted\n'
+EMPTY=
+`
+
+	env, err := ImportDotEnv(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+
+	want := []struct{ key, value string }{
+		{"DB_HOST", "localhost"},
+		{"DB_PORT", "5432"},
+		{"DB_USER", "admin"},
+		{"DB_PASSWORD", "p@ss # not a comment"},
+		{"GREETING", "hello\nworld \"quoted\""},
+		{"RAW", `single $quoted\n`},
+		{"EMPTY", ""},
+	}
+
+	if len(env.Spec.Values) != len(want) {
+		t.Fatalf("expected %d variables, got %d", len(want), len(env.Spec.Values))
+	}
+
+	for i, w := range want {
+		got := env.Spec.Values[i]
+		if got.Key != w.key || got.Value != w.value {
+			t.Errorf("variable %d: expected %s=%q, got %s=%q", i, w.key, w.value, got.Key, got.Value)
+		}
+
+		if !got.Enable || got.ID == "" {
+			t.Errorf("variable %s: expected it to be enabled and have an id", got.Key)
+		}
+	}
+}
+
+func TestImportDotEnv_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"missing equal sign":     "JUST_A_KEY",
+		"missing key":            "=value",
+		"unterminated quote":     `KEY="value`,
+		"unterminated raw quote": `KEY='value`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ImportDotEnv(strings.NewReader(data)); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
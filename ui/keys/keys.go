@@ -8,13 +8,22 @@ import (
 )
 
 func OnSaveCommand(gtx layout.Context, receiver any, callback func()) {
+	onShortcut(gtx, receiver, "S", callback)
+}
+
+// OnCommandPaletteCommand calls callback on Ctrl+K (Cmd+K on macOS).
+func OnCommandPaletteCommand(gtx layout.Context, receiver any, callback func()) {
+	onShortcut(gtx, receiver, "K", callback)
+}
+
+func onShortcut(gtx layout.Context, receiver any, name key.Name, callback func()) {
 	area := clip.Rect{Max: gtx.Constraints.Max}.Push(gtx.Ops)
 	event.Op(gtx.Ops, receiver)
 	for {
 		keyEvent, ok := gtx.Event(
 			key.Filter{
 				Required: key.ModShortcut,
-				Name:     "S",
+				Name:     name,
 			},
 		)
 		if !ok {
@@ -22,7 +31,7 @@ func OnSaveCommand(gtx layout.Context, receiver any, callback func()) {
 		}
 
 		if ev, ok := keyEvent.(key.Event); ok {
-			if ev.Name == "S" && ev.Modifiers.Contain(key.ModShortcut) && ev.State == key.Press {
+			if ev.Name == name && ev.Modifiers.Contain(key.ModShortcut) && ev.State == key.Press {
 				callback()
 			}
 		}
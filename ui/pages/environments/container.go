@@ -4,15 +4,18 @@ import (
 	"gioui.org/layout"
 	"gioui.org/unit"
 	"gioui.org/widget"
+	"gioui.org/widget/material"
 	"github.com/chapar-rest/chapar/internal/domain"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 	"github.com/chapar-rest/chapar/ui/converter"
+	"github.com/chapar-rest/chapar/ui/pages/requests/restful"
 	"github.com/chapar-rest/chapar/ui/widgets"
 )
 
 type container struct {
 	// env container
 	Items       *widgets.KeyValue
+	DefaultAuth *restful.Auth
 	Identifier  string
 	Title       *widgets.EditableLabel
 	SearchBox   *widgets.TextField
@@ -21,24 +24,34 @@ type container struct {
 	DataChanged bool
 }
 
-func newContainer(id, name string, items []domain.KeyValue) *container {
+func newContainer(id, name string, spec domain.EnvSpec, theme *chapartheme.Theme) *container {
 	search := widgets.NewTextField("", "Search items")
 	search.SetIcon(widgets.SearchIcon, widgets.IconPositionEnd)
 
 	c := &container{
-		Identifier: id,
-		Items:      widgets.NewKeyValue(converter.WidgetItemsFromKeyValue(items)...),
-		Title:      widgets.NewEditableLabel(name),
-		SearchBox:  search,
-		SaveButton: widget.Clickable{},
-		Prompt:     widgets.NewPrompt("Save", "", widgets.ModalTypeWarn),
+		Identifier:  id,
+		Items:       widgets.NewKeyValue(converter.WidgetItemsFromKeyValue(spec.Values)...),
+		DefaultAuth: restful.NewAuth(defaultAuth(spec), theme),
+		Title:       widgets.NewEditableLabel(name),
+		SearchBox:   search,
+		SaveButton:  widget.Clickable{},
+		Prompt:      widgets.NewPrompt("Save", "", widgets.ModalTypeWarn),
 	}
 	c.Prompt.WithoutRememberBool()
 	return c
 }
 
-func (c *container) SetItems(items []domain.KeyValue) {
-	c.Items.SetItems(converter.WidgetItemsFromKeyValue(items))
+func defaultAuth(spec domain.EnvSpec) domain.Auth {
+	if spec.DefaultAuth == nil {
+		return domain.Auth{Type: domain.AuthTypeNone}
+	}
+	// clone so the form edits do not change the environment in place
+	return spec.DefaultAuth.Clone()
+}
+
+func (c *container) SetSpec(spec domain.EnvSpec) {
+	c.Items.SetItems(converter.WidgetItemsFromKeyValue(spec.Values))
+	c.DefaultAuth.SetAuth(defaultAuth(spec))
 }
 
 func (c *container) Layout(gtx layout.Context, theme *chapartheme.Theme, selectedID string) layout.Dimensions {
@@ -74,6 +87,14 @@ func (c *container) Layout(gtx layout.Context, theme *chapartheme.Theme, selecte
 					)
 				})
 			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return material.Label(theme.Material(), theme.TextSize, "Default auth, used by the requests without auth").Layout(gtx)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(15)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return c.DefaultAuth.Layout(gtx, theme)
+				})
+			}),
 			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
 				return c.Items.WithAddLayout(gtx, "", "Disabled items have no effect on your requests", theme)
 			}),
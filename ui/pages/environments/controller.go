@@ -1,7 +1,11 @@
 package environments
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/chapar-rest/chapar/internal/domain"
 	"github.com/chapar-rest/chapar/internal/repository"
@@ -36,6 +40,7 @@ func NewController(view *View, repo repository.Repository, envState *state.Envir
 	view.SetOnTreeViewNodeClicked(c.onTreeViewNodeDoubleClicked)
 	view.SetOnTabSelected(c.onTabSelected)
 	view.SetOnItemsChanged(c.onItemsChanged)
+	view.SetOnDefaultAuthChanged(c.onDefaultAuthChanged)
 	view.SetOnSave(c.onSave)
 	view.SetOnTabClose(c.onTabClose)
 	view.SetOnTreeViewMenuClicked(c.onTreeViewMenuClicked)
@@ -68,6 +73,12 @@ func (c *Controller) onImportEnvironment() {
 			return
 		}
 
+		// the file path is not known on every platform, a .env file is never valid json
+		if !json.Valid(result.Data) {
+			c.importDotEnv(result)
+			return
+		}
+
 		if err := importer.ImportPostmanEnvironment(result.Data); err != nil {
 			fmt.Println("failed to import postman environment", err)
 			return
@@ -78,7 +89,33 @@ func (c *Controller) onImportEnvironment() {
 			return
 		}
 
-	}, "json")
+	}, "json", "env")
+}
+
+func (c *Controller) importDotEnv(result explorer.Result) {
+	env, err := importer.ImportDotEnv(bytes.NewReader(result.Data))
+	if err != nil {
+		fmt.Println("failed to import .env file", err)
+		return
+	}
+
+	// staging.env becomes staging, a plain .env file keeps the default name
+	if name := strings.TrimSuffix(filepath.Base(result.FilePath), ".env"); name != "" && name != "." {
+		env.MetaData.Name = name
+	}
+
+	filePath, err := c.repo.GetNewEnvironmentFilePath(env.MetaData.Name)
+	if err != nil {
+		fmt.Println("failed to get new environment file path", err)
+		return
+	}
+
+	env.FilePath = filePath.Path
+	env.MetaData.Name = filePath.NewName
+
+	c.state.AddEnvironment(env, state.SourceController)
+	c.view.AddTreeViewNode(env)
+	c.saveEnvironmentToDisc(env.MetaData.ID)
 }
 
 func (c *Controller) onEnvironmentChange(env *domain.Environment, source state.Source, action state.Action) {
@@ -169,19 +206,39 @@ func (c *Controller) onItemsChanged(id string, items []domain.KeyValue) {
 	}
 
 	env.Spec.Values = items
+	c.updateEnvironmentSpec(env)
+}
+
+func (c *Controller) onDefaultAuthChanged(id string, auth domain.Auth) {
+	env := c.state.GetEnvironment(id)
+	if env == nil {
+		return
+	}
+
+	if auth.Type == domain.AuthTypeNone || auth.Type == "" {
+		env.Spec.DefaultAuth = nil
+	} else {
+		clone := auth.Clone()
+		env.Spec.DefaultAuth = &clone
+	}
+
+	c.updateEnvironmentSpec(env)
+}
+
+func (c *Controller) updateEnvironmentSpec(env *domain.Environment) {
 	if err := c.state.UpdateEnvironment(env, state.SourceController, true); err != nil {
 		fmt.Println("failed to update environment", err)
 		return
 	}
 
 	// set tab dirty if the in memory data is different from the file
-	envFromFile, err := c.state.GetEnvironmentFromDisc(id)
+	envFromFile, err := c.state.GetEnvironmentFromDisc(env.MetaData.ID)
 	if err != nil {
 		fmt.Println("failed to get environment from file", err)
 		return
 	}
 
-	c.view.SetTabDirty(id, !domain.CompareKeyValues(env.Spec.Values, envFromFile.Spec.Values))
+	c.view.SetTabDirty(env.MetaData.ID, !domain.CompareEnvSpecs(env.Spec, envFromFile.Spec))
 }
 
 func (c *Controller) onSave(id string) {
@@ -205,7 +262,7 @@ func (c *Controller) onTabClose(id string) {
 	}
 
 	// if data is not changed close the tab
-	if domain.CompareKeyValues(env.Spec.Values, envFromFile.Spec.Values) {
+	if domain.CompareEnvSpecs(env.Spec, envFromFile.Spec) {
 		c.view.CloseTab(id)
 		return
 	}
@@ -21,6 +21,8 @@ const (
 )
 
 type View struct {
+	theme *chapartheme.Theme
+
 	newEnvButton widget.Clickable
 	importButton widget.Clickable
 
@@ -36,6 +38,7 @@ type View struct {
 	onImportEnv           func()
 	onTabClose            func(id string)
 	onItemsChanged        func(id string, items []domain.KeyValue)
+	onDefaultAuthChanged  func(id string, auth domain.Auth)
 	onSave                func(id string)
 	onTreeViewNodeClicked func(id string)
 	onTreeViewMenuClicked func(id string, action string)
@@ -47,6 +50,9 @@ type View struct {
 	treeViewNodes *safemap.Map[*widgets.TreeNode]
 
 	tipsView *tips.Tips
+
+	// authProfiles are the profiles the default auth of the environments can use
+	authProfiles []*domain.AuthProfile
 }
 
 func NewView(theme *chapartheme.Theme) *View {
@@ -59,6 +65,7 @@ func NewView(theme *chapartheme.Theme) *View {
 	itemsSearchBox.SetBorderColor(theme.BorderColor)
 
 	v := &View{
+		theme:             theme,
 		treeViewSearchBox: search,
 		tabHeader:         widgets.NewTabs([]*widgets.Tab{}, nil),
 		treeView:          widgets.NewTreeView([]*widgets.TreeNode{}),
@@ -135,6 +142,18 @@ func (v *View) SetOnItemsChanged(onItemsChanged func(id string, items []domain.K
 	v.onItemsChanged = onItemsChanged
 }
 
+// SetAuthProfiles lists the auth profiles the default auth of the environments can use.
+func (v *View) SetAuthProfiles(profiles []*domain.AuthProfile) {
+	v.authProfiles = profiles
+	for _, ct := range v.containers.Values() {
+		ct.DefaultAuth.SetProfiles(profiles)
+	}
+}
+
+func (v *View) SetOnDefaultAuthChanged(onDefaultAuthChanged func(id string, auth domain.Auth)) {
+	v.onDefaultAuthChanged = onDefaultAuthChanged
+}
+
 func (v *View) SetOnTreeViewNodeClicked(onTreeViewNodeClicked func(id string)) {
 	v.onTreeViewNodeClicked = onTreeViewNodeClicked
 	v.treeView.OnNodeClick(func(node *widgets.TreeNode) {
@@ -240,7 +259,7 @@ func (v *View) OpenContainer(env *domain.Environment) {
 		return
 	}
 
-	ct := newContainer(env.MetaData.ID, env.MetaData.Name, env.Spec.Values)
+	ct := newContainer(env.MetaData.ID, env.MetaData.Name, env.Spec, v.theme)
 	ct.Title.SetOnChanged(func(text string) {
 		if v.onTitleChanged != nil {
 			v.onTitleChanged(env.MetaData.ID, text)
@@ -253,6 +272,13 @@ func (v *View) OpenContainer(env *domain.Environment) {
 		}
 	})
 
+	ct.DefaultAuth.SetProfiles(v.authProfiles)
+	ct.DefaultAuth.SetOnChange(func(auth domain.Auth) {
+		if v.onDefaultAuthChanged != nil {
+			v.onDefaultAuthChanged(env.MetaData.ID, auth)
+		}
+	})
+
 	ct.SearchBox.SetOnTextChange(func(text string) {
 		if ct.Items == nil {
 			return
@@ -265,7 +291,7 @@ func (v *View) OpenContainer(env *domain.Environment) {
 
 func (v *View) ReloadContainerData(env *domain.Environment) {
 	if ct, ok := v.containers.Get(env.MetaData.ID); ok {
-		ct.SetItems(env.Spec.Values)
+		ct.SetSpec(env.Spec)
 	}
 }
 
@@ -12,7 +12,8 @@ import (
 )
 
 type AddressBar struct {
-	url *widget.Editor
+	url       *widget.Editor
+	completer *widgets.VariableCompleter
 
 	lastSelectedMethod string
 	methodDropDown     *widgets.DropDown
@@ -20,9 +21,15 @@ type AddressBar struct {
 	sendClickable widget.Clickable
 	sendButton    material.ButtonStyle
 
+	// sending turns the send button into a stop button while the request is in flight.
+	sending bool
+	// issues disable the send button and are listed under the address bar.
+	issues []string
+
 	onURLChanged    func(url string)
 	onMethodChanged func(method string)
 	onSubmit        func()
+	onCancel        func()
 }
 
 func NewAddressBar(theme *chapartheme.Theme, address, method string) *AddressBar {
@@ -33,6 +40,7 @@ func NewAddressBar(theme *chapartheme.Theme, address, method string) *AddressBar
 	}
 
 	a.url.SingleLine = true
+	a.completer = widgets.NewVariableCompleter(a.url)
 	a.url.SetText(address)
 
 	methods := []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD"}
@@ -67,6 +75,23 @@ func (a *AddressBar) SetOnSubmit(onSubmit func()) {
 	a.onSubmit = onSubmit
 }
 
+func (a *AddressBar) SetOnCancel(onCancel func()) {
+	a.onCancel = onCancel
+}
+
+func (a *AddressBar) SetSending(sending bool) {
+	a.sending = sending
+}
+
+func (a *AddressBar) SetIssues(issues []string) {
+	a.issues = issues
+}
+
+// SetVariableNames sets where the names suggested when typing {{ in the url come from.
+func (a *AddressBar) SetVariableNames(names func() []string) {
+	a.completer.SetNames(names)
+}
+
 func (a *AddressBar) SetURL(url string) {
 	a.url.SetText(url)
 }
@@ -83,6 +108,7 @@ func (a *AddressBar) Layout(gtx layout.Context, theme *chapartheme.Theme) layout
 		CornerRadius: unit.Dp(4),
 	}
 
+	a.completer.Update(gtx)
 	for {
 		event, ok := a.url.Update(gtx)
 		if !ok {
@@ -102,6 +128,28 @@ func (a *AddressBar) Layout(gtx layout.Context, theme *chapartheme.Theme) layout
 		}
 	}
 
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return a.layoutBar(gtx, theme, border)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return a.completer.Layout(gtx, theme)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if len(a.issues) == 0 {
+				return layout.Dimensions{}
+			}
+
+			return layout.Inset{Top: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				l := material.Label(theme.Material(), unit.Sp(12), strings.Join(a.issues, ", "))
+				l.Color = theme.ErrorColor
+				return l.Layout(gtx)
+			})
+		}),
+	)
+}
+
+func (a *AddressBar) layoutBar(gtx layout.Context, theme *chapartheme.Theme, border widget.Border) layout.Dimensions {
 	return layout.Flex{
 		Axis:      layout.Horizontal,
 		Alignment: layout.Middle,
@@ -133,8 +181,16 @@ func (a *AddressBar) Layout(gtx layout.Context, theme *chapartheme.Theme) layout
 			})
 		}),
 		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if len(a.issues) > 0 && !a.sending {
+				gtx = gtx.Disabled()
+			}
+
 			if a.sendClickable.Clicked(gtx) {
-				if a.onSubmit != nil {
+				if a.sending {
+					if a.onCancel != nil {
+						go a.onCancel()
+					}
+				} else if a.onSubmit != nil {
 					go a.onSubmit()
 				}
 			}
@@ -142,6 +198,10 @@ func (a *AddressBar) Layout(gtx layout.Context, theme *chapartheme.Theme) layout
 			gtx.Constraints.Min.X = gtx.Dp(80)
 			btn := material.Button(theme.Material(), &a.sendClickable, "Send")
 			btn.Background = theme.SendButtonBgColor
+			if a.sending {
+				btn.Text = "Stop"
+				btn.Background = theme.ErrorColor
+			}
 			btn.Color = theme.ButtonTextColor
 			return btn.Layout(gtx)
 		}),
@@ -6,6 +6,7 @@ import (
 
 	"gioui.org/layout"
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/rest"
 )
 
 const (
@@ -40,4 +41,16 @@ type RestContainer interface {
 	SetBinaryBodyFilePath(filePath string)
 	SetOnFormDataFileSelect(f func(requestId, fieldId string))
 	AddFileToFormData(fieldId, filePath string)
+	SetSplitAxis(axis layout.Axis)
+	SetOnUpdateSnapshot(f func(id string))
+	SetOnSaveFixture(f func(id string))
+	SetOnCancel(f func(id string))
+	SetSnapshot(snapshot *domain.ResponseSnapshot)
+	SetSnapshotResult(result *rest.SnapshotResult)
+	SetValidationIssues(issues []rest.ValidationIssue)
+	SetEnvironments(envs []*domain.Environment)
+	SetAuthProfiles(profiles []*domain.AuthProfile)
+	SetVariableNames(names func() []string)
+	SetOnSaveAuthProfile(f func(name string, auth domain.Auth))
+	SetMaxRenderBytes(maxBytes int)
 }
@@ -1,9 +1,13 @@
 package requests
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,19 +15,25 @@ import (
 	"gioui.org/layout"
 	"github.com/chapar-rest/chapar/internal/domain"
 	"github.com/chapar-rest/chapar/internal/notify"
+	"github.com/chapar-rest/chapar/internal/palette"
 	"github.com/chapar-rest/chapar/internal/repository"
 	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/internal/safemap"
 	"github.com/chapar-rest/chapar/internal/state"
 	"github.com/chapar-rest/chapar/ui/explorer"
 	"github.com/chapar-rest/chapar/ui/importer"
 	"github.com/chapar-rest/chapar/ui/widgets"
 )
 
+// repeatCount is how many times the repeat menu action sends a request, it matches MenuRepeat.
+const repeatCount = 5
+
 type Controller struct {
 	model *state.Requests
 	view  *View
 
-	envState *state.Environments
+	envState     *state.Environments
+	authProfiles *state.AuthProfiles
 
 	repo repository.Repository
 
@@ -32,18 +42,27 @@ type Controller struct {
 	explorer *explorer.Explorer
 
 	restService *rest.Service
+
+	// fixtures keeps the fixture of the last response of each request, for saving it on demand
+	fixtures *safemap.Map[*rest.Fixture]
+
+	// inFlight keeps the cancel func of each request being sent, for the stop button
+	inFlight *safemap.Map[context.CancelFunc]
 }
 
-func NewController(view *View, repo repository.Repository, model *state.Requests, envState *state.Environments, explorer *explorer.Explorer, restService *rest.Service) *Controller {
+func NewController(view *View, repo repository.Repository, model *state.Requests, envState *state.Environments, authProfiles *state.AuthProfiles, explorer *explorer.Explorer, restService *rest.Service) *Controller {
 	c := &Controller{
-		view:     view,
-		model:    model,
-		repo:     repo,
-		envState: envState,
+		view:         view,
+		model:        model,
+		repo:         repo,
+		envState:     envState,
+		authProfiles: authProfiles,
 
 		explorer: explorer,
 
 		restService: restService,
+		fixtures:    safemap.New[*rest.Fixture](),
+		inFlight:    safemap.New[context.CancelFunc](),
 	}
 
 	view.SetOnNewRequest(c.onNewRequest)
@@ -60,9 +79,48 @@ func NewController(view *View, repo repository.Repository, model *state.Requests
 	view.SetOnBinaryFileSelect(c.onSelectBinaryFile)
 	view.SetOnPostRequestSetChanged(c.onPostRequestSetChanged)
 	view.SetOnFormDataFileSelect(c.onFormDataFileSelect)
+	view.SetOnUpdateSnapshot(c.onUpdateSnapshot)
+	view.SetOnSaveFixture(c.onSaveFixture)
+	view.SetOnCancel(c.onCancelRequest)
+	view.SetOnSaveAuthProfile(c.onSaveAuthProfile)
+
+	envState.AddEnvironmentChangeListener(func(_ *domain.Environment, _ state.Source, _ state.Action) {
+		c.view.SetAllEnvironments(c.environments())
+		c.validateRequests()
+	})
+	envState.OnActiveChanged(func(_ string) {
+		c.validateRequests()
+	})
+	authProfiles.AddAuthProfileChangeListener(func(_ *domain.AuthProfile, _ state.Action) {
+		c.view.SetAllAuthProfiles(c.authProfiles.GetAuthProfiles())
+	})
 	return c
 }
 
+// environments returns the environments sorted by name for the request environment picker.
+func (c *Controller) environments() []*domain.Environment {
+	envs := c.envState.GetEnvironments()
+	sort.Slice(envs, func(i, j int) bool {
+		return envs[i].MetaData.Name < envs[j].MetaData.Name
+	})
+	return envs
+}
+
+// onSaveAuthProfile saves auth as a new auth profile, so other requests can use it.
+func (c *Controller) onSaveAuthProfile(name string, auth domain.Auth) {
+	profile := domain.NewAuthProfile(name)
+	profile.Spec = auth.Clone()
+
+	c.authProfiles.AddAuthProfile(profile)
+	if err := c.authProfiles.UpdateAuthProfile(profile, false); err != nil {
+		fmt.Println("failed to save auth profile", err)
+		_ = c.authProfiles.RemoveAuthProfile(profile, true)
+		return
+	}
+
+	notify.Send("Auth profile "+profile.MetaData.Name+" saved", 2*time.Second)
+}
+
 func (c *Controller) LoadData() error {
 	collections, err := c.model.LoadCollectionsFromDisk()
 	if err != nil {
@@ -207,17 +265,27 @@ func (c *Controller) onCopyResponse(gtx layout.Context, response string) {
 }
 
 func (c *Controller) onSubmitRequest(id string) {
+	if c.inFlight.Has(id) {
+		// the request is already being sent
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.inFlight.Set(id, cancel)
+	defer func() {
+		cancel()
+		c.inFlight.Delete(id)
+	}()
+
 	c.view.SetSendingRequestLoading(id)
 	defer c.view.SetSendingRequestLoaded(id)
 
-	var envID = ""
-	activeEnvironment := c.envState.GetActiveEnvironment()
-	if activeEnvironment != nil {
-		envID = activeEnvironment.MetaData.ID
-	}
-
-	res, err := c.restService.SendRequest(id, envID)
+	res, err := c.restService.SendRequestContext(ctx, id, c.activeEnvironmentID())
 	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			err = errors.New("request cancelled")
+		}
+
 		c.view.SetHTTPResponse(id, domain.HTTPResponseDetail{
 			Error: err,
 		})
@@ -236,9 +304,210 @@ func (c *Controller) onSubmitRequest(id string) {
 		StatusCode: res.StatusCode,
 		Duration:   res.TimePassed,
 		Size:       len(res.Body),
+
+		RemainingDeadline: res.RemainingDeadline,
+		TraceID:           res.TraceID,
+	})
+
+	if len(res.Warnings) > 0 {
+		notify.Send(strings.Join(res.Warnings, "\n"), 5*time.Second)
+	}
+
+	if fixture := res.Fixture(); fixture != nil {
+		c.fixtures.Set(id, fixture)
+	}
+
+	if snapshot := res.Snapshot; snapshot != nil {
+		// the request and its editor belong to the UI goroutine
+		c.view.RunOnUI(func() {
+			c.view.SetSnapshotResult(id, snapshot)
+			if snapshot.Recorded != nil {
+				if _, err := c.restService.UpdateSnapshot(id, snapshot.Recorded.Body); err != nil {
+					fmt.Println("failed to record snapshot", err)
+					return
+				}
+				c.onSnapshotChanged(id)
+			}
+		})
+	}
+}
+
+// onCancelRequest aborts the request being sent, onSubmitRequest then restores the send button.
+func (c *Controller) onCancelRequest(id string) {
+	if cancel, ok := c.inFlight.Get(id); ok {
+		cancel()
+	}
+}
+
+// Commands returns the command palette commands of the requests page: sending the
+// request of the selected tab and opening any request.
+func (c *Controller) Commands() []palette.Command {
+	commands := []palette.Command{
+		{
+			Title: "Send request",
+			Run: func() {
+				if id := c.view.SelectedTabID(); c.view.GetTabType(id) == TypeRequest {
+					go c.onSubmitRequest(id)
+				}
+			},
+		},
+	}
+
+	reqs := c.model.GetRequests()
+	sort.Slice(reqs, func(i, j int) bool {
+		return reqs[i].MetaData.Name < reqs[j].MetaData.Name
+	})
+
+	for _, req := range reqs {
+		title := "Open request: " + req.MetaData.Name
+		if req.CollectionName != "" {
+			title = "Open request: " + req.CollectionName + " / " + req.MetaData.Name
+		}
+
+		id := req.MetaData.ID
+		commands = append(commands, palette.Command{
+			Title: title,
+			Run:   func() { c.viewRequest(id) },
+		})
+	}
+
+	return commands
+}
+
+func (c *Controller) activeEnvironmentID() string {
+	if activeEnvironment := c.envState.GetActiveEnvironment(); activeEnvironment != nil {
+		return activeEnvironment.MetaData.ID
+	}
+	return ""
+}
+
+// repeatRequest sends the request repeatCount times in a row and reports a summary of the responses.
+func (c *Controller) repeatRequest(id string) {
+	_, agg := c.restService.SendRequestRepeat(id, c.activeEnvironmentID(), repeatCount)
+
+	summary := fmt.Sprintf("%d of %d succeeded, %d failed", agg.Successes, agg.Count, agg.Failures)
+	if agg.Count > agg.Failures {
+		summary += fmt.Sprintf(", latency min %s, avg %s, max %s",
+			agg.MinLatency.Round(time.Millisecond), agg.AvgLatency.Round(time.Millisecond), agg.MaxLatency.Round(time.Millisecond))
+	}
+
+	notify.Send(summary, 5*time.Second)
+}
+
+// matrixRequest sends the request once against every environment and reports the status of each.
+func (c *Controller) matrixRequest(id string) {
+	envs := c.environments()
+	if len(envs) == 0 {
+		notify.Send("There are no environments to run the request in", 2*time.Second)
+		return
+	}
+
+	ids := make([]string, 0, len(envs))
+	for _, env := range envs {
+		ids = append(ids, env.MetaData.ID)
+	}
+
+	responses, err := c.restService.SendRequestMatrix(id, ids)
+	if err != nil {
+		fmt.Println("failed to run request in all environments", err)
+	}
+
+	lines := make([]string, 0, len(envs))
+	for _, env := range envs {
+		res, ok := responses[env.MetaData.ID]
+		if !ok {
+			lines = append(lines, env.MetaData.Name+": failed")
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %d in %s", env.MetaData.Name, res.StatusCode, res.TimePassed.Round(time.Millisecond)))
+	}
+
+	notify.Send(strings.Join(lines, "\n"), 5*time.Second)
+}
+
+func (c *Controller) onSaveFixture(id string) {
+	fixture, ok := c.fixtures.Get(id)
+	if !ok {
+		notify.Send("Send the request first to save it as a fixture", 3*time.Second)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := rest.WriteFixture(&buf, fixture); err != nil {
+		fmt.Println("failed to write fixture", err)
+		return
+	}
+
+	name := "fixture.yaml"
+	if req := c.model.GetRequest(id); req != nil {
+		name = req.MetaData.Name + ".fixture.yaml"
+	}
+
+	c.explorer.SaveFile(name, buf.Bytes(), func(r explorer.Result) {
+		if r.Error != nil {
+			fmt.Println("failed to save fixture", r.Error)
+			return
+		}
+
+		notify.Send("Fixture saved", 2*time.Second)
+	})
+}
+
+func (c *Controller) exportAsGoCode(id string) {
+	code, err := c.restService.ExportAsGoCode(id, c.activeEnvironmentID())
+	if err != nil {
+		fmt.Println("failed to export request as go code", err)
+		notify.Send("Failed to export request as Go code", 3*time.Second)
+		return
+	}
+
+	name := "main.go"
+	if req := c.model.GetRequest(id); req != nil {
+		name = req.MetaData.Name + ".go"
+	}
+
+	c.explorer.SaveFile(name, []byte(code), func(r explorer.Result) {
+		if r.Error != nil {
+			fmt.Println("failed to save go code", r.Error)
+			return
+		}
+
+		notify.Send("Go code saved", 2*time.Second)
 	})
 }
 
+func (c *Controller) onUpdateSnapshot(id string) {
+	res := c.view.GetHTTPResponse(id)
+	if res == nil {
+		return
+	}
+
+	if _, err := c.restService.UpdateSnapshot(id, res.Response); err != nil {
+		fmt.Println("failed to update snapshot", err)
+		return
+	}
+
+	c.onSnapshotChanged(id)
+}
+
+// onSnapshotChanged syncs the editor with the snapshot stored in the state,
+// the request has to be saved to keep it.
+func (c *Controller) onSnapshotChanged(id string) {
+	req := c.model.GetRequest(id)
+	if req == nil {
+		return
+	}
+
+	c.view.SetSnapshot(id, req.Spec.HTTP.Snapshot)
+
+	reqFromFile, err := c.model.GetRequestFromDisc(id)
+	if err != nil {
+		fmt.Println("failed to get request from file", err)
+		return
+	}
+	c.view.SetTabDirty(id, !domain.CompareRequests(req, reqFromFile))
+}
+
 func cookieToKeyValue(cookies []*http.Cookie) []domain.KeyValue {
 	var kvs []domain.KeyValue
 	for _, c := range cookies {
@@ -318,9 +587,7 @@ func (c *Controller) onRequestDataChanged(id string, data any) {
 		inComingRequest.Spec.HTTP.Request.PathParams = newPathParams
 	}
 
-	// break the reference
-	clone := inComingRequest.Clone()
-	req.Spec = clone.Spec
+	req.ApplyEdits(inComingRequest)
 
 	if err := c.model.UpdateRequest(req, true); err != nil {
 		fmt.Println("failed to update request", err)
@@ -334,6 +601,19 @@ func (c *Controller) onRequestDataChanged(id string, data any) {
 		return
 	}
 	c.view.SetTabDirty(id, !domain.CompareRequests(req, reqFromFile))
+	c.validateRequest(id)
+}
+
+// validateRequest shows the issues that would make sending the request fail, the send button is disabled while there are any.
+func (c *Controller) validateRequest(id string) {
+	c.view.SetValidationIssues(id, c.restService.ValidateRequest(id, c.activeEnvironmentID()))
+}
+
+// validateRequests validates every request, the view only shows the issues of the open ones.
+func (c *Controller) validateRequests() {
+	for _, req := range c.model.GetRequests() {
+		c.validateRequest(req.MetaData.ID)
+	}
 }
 
 func (c *Controller) getNewURLWithParams(params []domain.KeyValue, url string) string {
@@ -489,6 +769,7 @@ func (c *Controller) onNewRequest() {
 	c.saveRequestToDisc(req.MetaData.ID)
 	c.view.OpenTab(req.MetaData.ID, req.MetaData.Name, TypeRequest)
 	c.view.OpenRequestContainer(req)
+	c.setupRequestContainer(req.MetaData.ID)
 	c.view.SwitchToTab(req.MetaData.ID)
 }
 
@@ -541,6 +822,8 @@ func (c *Controller) saveRequestToDisc(id string) {
 		fmt.Println("failed to update request", err)
 		return
 	}
+	c.view.UpdateTreeNodeTags(id, req.MetaData.Tags)
+	c.view.UpdateTreeNodeColor(id, req.MetaData.Color)
 	c.view.SetTabDirty(id, false)
 }
 
@@ -569,6 +852,16 @@ func (c *Controller) onTreeViewMenuClicked(id, action string) {
 	switch action {
 	case MenuDuplicate:
 		c.duplicateRequest(id)
+	case MenuRepeat:
+		go c.repeatRequest(id)
+	case MenuMatrix:
+		go c.matrixRequest(id)
+	case MenuExportGo:
+		c.exportAsGoCode(id)
+	case MenuMoveUp:
+		c.moveTreeViewNode(id, -1)
+	case MenuMoveDown:
+		c.moveTreeViewNode(id, 1)
 	case MenuDelete:
 		switch nodeType {
 		case TypeRequest:
@@ -587,6 +880,31 @@ func (c *Controller) onTreeViewMenuClicked(id, action string) {
 	}
 }
 
+func (c *Controller) moveTreeViewNode(id string, delta int) {
+	index := c.model.IndexOf(id)
+	if index < 0 {
+		return
+	}
+
+	if err := c.model.Reorder(id, index+delta); err != nil {
+		fmt.Println("failed to reorder", err)
+		return
+	}
+
+	c.refreshTreeView()
+}
+
+func (c *Controller) refreshTreeView() {
+	requests := make([]*domain.Request, 0)
+	for _, req := range c.model.GetRequests() {
+		if req.CollectionID == "" {
+			requests = append(requests, req)
+		}
+	}
+
+	c.view.PopulateTreeView(requests, c.model.GetCollections())
+}
+
 func (c *Controller) addRequestToCollection(id string) {
 	req := domain.NewRequest("New Request")
 	col := c.model.GetCollection(id)
@@ -613,6 +931,7 @@ func (c *Controller) addRequestToCollection(id string) {
 	c.view.ExpandTreeViewNode(col.MetaData.ID)
 	c.view.OpenTab(req.MetaData.ID, req.MetaData.Name, TypeRequest)
 	c.view.OpenRequestContainer(req)
+	c.setupRequestContainer(req.MetaData.ID)
 	c.view.SwitchToTab(req.MetaData.ID)
 }
 
@@ -634,6 +953,24 @@ func (c *Controller) viewRequest(id string) {
 
 	c.view.OpenTab(req.MetaData.ID, req.MetaData.Name, TypeRequest)
 	c.view.OpenRequestContainer(clone)
+	c.setupRequestContainer(req.MetaData.ID)
+}
+
+// setupRequestContainer fills a newly opened request container with the data it needs from
+// outside the request, whether the request was just created or opened from the sidebar.
+func (c *Controller) setupRequestContainer(id string) {
+	c.view.SetEnvironments(id, c.environments())
+	c.view.SetAuthProfiles(id, c.authProfiles.GetAuthProfiles())
+	c.setVariableNames(id)
+	c.validateRequest(id)
+}
+
+// setVariableNames backs the {{ autocompletion of the request with the variables it can use
+// against the active environment.
+func (c *Controller) setVariableNames(id string) {
+	c.view.SetVariableNames(id, func() []string {
+		return c.restService.VariableNames(id, c.activeEnvironmentID())
+	})
 }
 
 func (c *Controller) viewCollection(id string) {
@@ -653,23 +990,17 @@ func (c *Controller) viewCollection(id string) {
 }
 
 func (c *Controller) duplicateRequest(id string) {
-	// read request from file to make sure we have the latest persisted data
-	reqFromFile, err := c.model.GetRequestFromDisc(id)
+	newReq, err := c.model.Duplicate(id)
 	if err != nil {
-		fmt.Println("failed to get request from file", err)
+		fmt.Println("failed to duplicate request", err)
 		return
 	}
 
-	newReq := reqFromFile.Clone()
-	newReq.MetaData.Name = newReq.MetaData.Name + " (copy)"
-	newReq.FilePath = repository.AddSuffixBeforeExt(newReq.FilePath, "-copy")
-	c.model.AddRequest(newReq)
-	if reqFromFile.CollectionID == "" {
+	if newReq.CollectionID == "" {
 		c.view.AddRequestTreeViewNode(newReq)
 	} else {
-		c.view.AddChildTreeViewNode(reqFromFile.CollectionID, newReq)
+		c.view.AddChildTreeViewNode(newReq.CollectionID, newReq)
 	}
-	c.saveRequestToDisc(newReq.MetaData.ID)
 }
 
 func (c *Controller) deleteRequest(id string) {
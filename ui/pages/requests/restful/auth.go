@@ -1,8 +1,13 @@
 package restful
 
 import (
+	"strconv"
+	"strings"
+
 	"gioui.org/layout"
 	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
 	"github.com/chapar-rest/chapar/internal/domain"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 	"github.com/chapar-rest/chapar/ui/pages/requests/component"
@@ -14,11 +19,22 @@ type Auth struct {
 
 	auth domain.Auth
 
-	TokenForm  *component.Form
-	BasicForm  *component.Form
-	APIKeyForm *component.Form
+	TokenForm   *component.Form
+	BasicForm   *component.Form
+	APIKeyForm  *component.Form
+	JWTForm     *component.Form
+	AWSForm     *component.Form
+	CommandForm *component.Form
+
+	// profileDropDown picks the auth profile used by the Profile type
+	profileDropDown *widgets.DropDown
+
+	// profileName and saveProfileButton save the current auth as a new profile
+	profileName       *widgets.TextField
+	saveProfileButton widget.Clickable
 
-	onChange func(auth domain.Auth)
+	onChange        func(auth domain.Auth)
+	onSaveAsProfile func(name string, auth domain.Auth)
 }
 
 func NewAuth(auth domain.Auth, theme *chapartheme.Theme) *Auth {
@@ -30,6 +46,10 @@ func NewAuth(auth domain.Auth, theme *chapartheme.Theme) *Auth {
 			widgets.NewDropDownOption("Basic").WithValue(domain.AuthTypeBasic),
 			widgets.NewDropDownOption("Token").WithValue(domain.AuthTypeToken),
 			widgets.NewDropDownOption("API Key").WithValue(domain.AuthTypeAPIKey),
+			widgets.NewDropDownOption("JWT").WithValue(domain.AuthTypeJWT),
+			widgets.NewDropDownOption("AWS Signature").WithValue(domain.AuthTypeAWSSigV4),
+			widgets.NewDropDownOption("Command").WithValue(domain.AuthTypeCommand),
+			widgets.NewDropDownOption("Profile").WithValue(domain.AuthTypeProfile),
 		),
 
 		TokenForm: component.NewForm([]*component.Field{
@@ -43,10 +63,33 @@ func NewAuth(auth domain.Auth, theme *chapartheme.Theme) *Auth {
 			{Label: "Key", Value: ""},
 			{Label: "Value", Value: ""},
 		}),
+		profileDropDown: widgets.NewDropDown(theme),
+		profileName:     widgets.NewTextField("", "Profile name"),
+		JWTForm: component.NewForm([]*component.Field{
+			{Label: "Algorithm", Value: ""},
+			{Label: "Secret", Value: ""},
+			{Label: "Claims", Value: ""},
+			{Label: "TTL (seconds)", Value: ""},
+		}),
+		AWSForm: component.NewForm([]*component.Field{
+			{Label: "Access Key", Value: ""},
+			{Label: "Secret Key", Value: ""},
+			{Label: "Session Token", Value: ""},
+			{Label: "Region", Value: ""},
+			{Label: "Service", Value: ""},
+		}),
+		CommandForm: component.NewForm([]*component.Field{
+			{Label: "Command", Value: ""},
+			{Label: "Header", Value: ""},
+			{Label: "Prefix", Value: ""},
+			{Label: "TTL (seconds)", Value: ""},
+		}),
 	}
 
 	a.DropDown.SetSelectedByValue(auth.Type)
 	a.DropDown.MinWidth = unit.Dp(150)
+	a.profileDropDown.MinWidth = unit.Dp(150)
+	a.SetProfiles(nil)
 
 	if auth.BasicAuth != nil {
 		a.BasicForm.SetValues(map[string]string{
@@ -68,9 +111,63 @@ func NewAuth(auth domain.Auth, theme *chapartheme.Theme) *Auth {
 		})
 	}
 
+	if auth.JWTAuth != nil {
+		a.JWTForm.SetValues(jwtFormValues(auth.JWTAuth))
+	} else {
+		a.JWTForm.SetValues(jwtFormValues(domain.NewJWTAuth()))
+	}
+
+	if auth.AWSSigV4Auth != nil {
+		a.AWSForm.SetValues(awsFormValues(auth.AWSSigV4Auth))
+	}
+
+	if auth.CommandAuth != nil {
+		a.CommandForm.SetValues(commandFormValues(auth.CommandAuth))
+	} else {
+		a.CommandForm.SetValues(commandFormValues(domain.NewCommandAuth()))
+	}
+
 	return a
 }
 
+func awsFormValues(auth *domain.AWSSigV4Auth) map[string]string {
+	return map[string]string{
+		"Access Key":    auth.AccessKey,
+		"Secret Key":    auth.SecretKey,
+		"Session Token": auth.SessionToken,
+		"Region":        auth.Region,
+		"Service":       auth.Service,
+	}
+}
+
+func commandFormValues(auth *domain.CommandAuth) map[string]string {
+	ttl := ""
+	if auth.TTLSeconds > 0 {
+		ttl = strconv.Itoa(auth.TTLSeconds)
+	}
+
+	return map[string]string{
+		"Command":       auth.Command,
+		"Header":        auth.Header,
+		"Prefix":        auth.Prefix,
+		"TTL (seconds)": ttl,
+	}
+}
+
+func jwtFormValues(auth *domain.JWTAuth) map[string]string {
+	ttl := ""
+	if auth.TTLSeconds > 0 {
+		ttl = strconv.Itoa(auth.TTLSeconds)
+	}
+
+	return map[string]string{
+		"Algorithm":     auth.Algorithm,
+		"Secret":        auth.Secret,
+		"Claims":        auth.Claims,
+		"TTL (seconds)": ttl,
+	}
+}
+
 func (a *Auth) SetOnChange(f func(auth domain.Auth)) {
 	a.onChange = f
 
@@ -107,6 +204,50 @@ func (a *Auth) SetOnChange(f func(auth domain.Auth)) {
 		a.auth.APIKeyAuth.Value = values["Value"]
 		a.onChange(a.auth)
 	})
+
+	a.profileDropDown.SetOnChanged(func(profileID string) {
+		a.auth.ProfileID = profileID
+		a.onChange(a.auth)
+	})
+
+	a.JWTForm.SetOnChange(func(values map[string]string) {
+		if a.auth.JWTAuth == nil {
+			a.auth.JWTAuth = domain.NewJWTAuth()
+		}
+
+		// an invalid ttl is treated as no ttl
+		ttl, _ := strconv.Atoi(values["TTL (seconds)"])
+
+		a.auth.JWTAuth.Algorithm = values["Algorithm"]
+		a.auth.JWTAuth.Secret = values["Secret"]
+		a.auth.JWTAuth.Claims = values["Claims"]
+		a.auth.JWTAuth.TTLSeconds = ttl
+		a.onChange(a.auth)
+	})
+
+	a.AWSForm.SetOnChange(func(values map[string]string) {
+		a.auth.AWSSigV4Auth = &domain.AWSSigV4Auth{
+			AccessKey:    values["Access Key"],
+			SecretKey:    values["Secret Key"],
+			SessionToken: values["Session Token"],
+			Region:       values["Region"],
+			Service:      values["Service"],
+		}
+		a.onChange(a.auth)
+	})
+
+	a.CommandForm.SetOnChange(func(values map[string]string) {
+		// an invalid ttl is treated as no ttl
+		ttl, _ := strconv.Atoi(values["TTL (seconds)"])
+
+		a.auth.CommandAuth = &domain.CommandAuth{
+			Command:    values["Command"],
+			Header:     values["Header"],
+			Prefix:     values["Prefix"],
+			TTLSeconds: ttl,
+		}
+		a.onChange(a.auth)
+	})
 }
 
 func (a *Auth) SetAuth(auth domain.Auth) {
@@ -132,6 +273,74 @@ func (a *Auth) SetAuth(auth domain.Auth) {
 			"Value": auth.APIKeyAuth.Value,
 		})
 	}
+
+	if auth.JWTAuth != nil {
+		a.JWTForm.SetValues(jwtFormValues(auth.JWTAuth))
+	} else {
+		a.JWTForm.SetValues(jwtFormValues(domain.NewJWTAuth()))
+	}
+
+	if auth.AWSSigV4Auth != nil {
+		a.AWSForm.SetValues(awsFormValues(auth.AWSSigV4Auth))
+	}
+
+	if auth.CommandAuth != nil {
+		a.CommandForm.SetValues(commandFormValues(auth.CommandAuth))
+	} else {
+		a.CommandForm.SetValues(commandFormValues(domain.NewCommandAuth()))
+	}
+
+	a.profileDropDown.SetSelectedByValue(auth.ProfileID)
+}
+
+// SetProfiles lists the auth profiles the Profile type can use.
+func (a *Auth) SetProfiles(profiles []*domain.AuthProfile) {
+	options := make([]*widgets.DropDownOption, 0, len(profiles)+1)
+	options = append(options, widgets.NewDropDownOption("Select a profile").WithValue(""))
+	for _, p := range profiles {
+		options = append(options, widgets.NewDropDownOption(p.MetaData.Name).WithValue(p.MetaData.ID))
+	}
+
+	a.profileDropDown.SetOptions(options...)
+	a.profileDropDown.SetSelected(0)
+	a.profileDropDown.SetSelectedByValue(a.auth.ProfileID)
+}
+
+// SetOnSaveAsProfile is called with the name and the current auth when it is saved as a profile.
+func (a *Auth) SetOnSaveAsProfile(f func(name string, auth domain.Auth)) {
+	a.onSaveAsProfile = f
+}
+
+// canSaveAsProfile reports whether the selected type holds credentials that can become a profile.
+func (a *Auth) canSaveAsProfile() bool {
+	t := a.DropDown.GetSelected().Value
+	return a.onSaveAsProfile != nil && t != domain.AuthTypeNone && t != domain.AuthTypeProfile
+}
+
+func (a *Auth) saveAsProfileLayout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if a.saveProfileButton.Clicked(gtx) {
+		if name := strings.TrimSpace(a.profileName.GetText()); name != "" {
+			auth := a.auth.Clone()
+			auth.Type = a.DropDown.GetSelected().Value
+			a.onSaveAsProfile(name, auth)
+			a.profileName.SetText("")
+		}
+	}
+
+	return layout.Inset{Top: unit.Dp(15)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Max.X = gtx.Dp(200)
+				return a.profileName.Layout(gtx, theme)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(10)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				btn := widgets.Button(theme.Material(), &a.saveProfileButton, nil, widgets.IconPositionStart, "Save as profile")
+				btn.Color = theme.ButtonTextColor
+				return btn.Layout(gtx, theme)
+			}),
+		)
+	})
 }
 
 func (a *Auth) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
@@ -156,9 +365,32 @@ func (a *Auth) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimen
 				return a.BasicForm.Layout(gtx, theme)
 			case "API Key":
 				return a.APIKeyForm.Layout(gtx, theme)
+			case "JWT":
+				return a.JWTForm.Layout(gtx, theme)
+			case "AWS Signature":
+				return a.AWSForm.Layout(gtx, theme)
+			case "Command":
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return a.CommandForm.Layout(gtx, theme)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						l := material.Label(theme.Material(), unit.Sp(12), "The command runs with your privileges and without a shell, variables are passed to it as CHAPAR_<name> environment variables.")
+						l.Color = theme.TextColor
+						return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, l.Layout)
+					}),
+				)
+			case "Profile":
+				return a.profileDropDown.Layout(gtx, theme)
 			default:
 				return layout.Dimensions{}
 			}
 		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if !a.canSaveAsProfile() {
+				return layout.Dimensions{}
+			}
+			return a.saveAsProfileLayout(gtx, theme)
+		}),
 	)
 }
@@ -1,9 +1,14 @@
 package restful
 
 import (
+	"time"
+
 	"gioui.org/layout"
 	"gioui.org/unit"
+	"gioui.org/widget"
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/notify"
+	"github.com/chapar-rest/chapar/internal/rest"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 	"github.com/chapar-rest/chapar/ui/converter"
 	"github.com/chapar-rest/chapar/ui/pages/requests/component"
@@ -20,6 +25,8 @@ type Body struct {
 	script     *widgets.CodeEditor
 	BinaryFile *component.BinaryFile
 
+	formatButton widget.Clickable
+
 	onChange func(body domain.Body)
 }
 
@@ -57,6 +64,11 @@ func NewBody(body domain.Body, theme *chapartheme.Theme) *Body {
 	return b
 }
 
+// SetVariableNames sets where the names suggested when typing {{ in the body come from.
+func (b *Body) SetVariableNames(names func() []string) {
+	b.script.SetVariableNames(names)
+}
+
 func (b *Body) SetOnChange(f func(body domain.Body)) {
 	b.onChange = f
 
@@ -90,7 +102,26 @@ func (b *Body) SetOnChange(f func(body domain.Body)) {
 	})
 }
 
+// formatJSON re-indents the JSON body, reporting where it is invalid.
+func (b *Body) formatJSON() {
+	formatted, err := rest.FormatJSON(b.script.Code())
+	if err != nil {
+		notify.Send(err.Error(), 3*time.Second)
+		return
+	}
+
+	b.script.SetCode(formatted)
+	b.body.Data = formatted
+	if b.onChange != nil {
+		b.onChange(b.body)
+	}
+}
+
 func (b *Body) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if b.formatButton.Clicked(gtx) {
+		b.formatJSON()
+	}
+
 	inset := layout.Inset{Top: unit.Dp(15), Right: unit.Dp(10)}
 	return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 		return layout.Flex{
@@ -102,6 +133,17 @@ func (b *Body) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimen
 					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 						return b.DropDown.Layout(gtx, theme)
 					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						if b.DropDown.GetSelected().Value != domain.BodyTypeJSON {
+							return layout.Dimensions{}
+						}
+
+						return layout.Inset{Left: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							btn := widgets.Button(theme.Material(), &b.formatButton, nil, widgets.IconPositionStart, "Format")
+							btn.Color = theme.ButtonTextColor
+							return btn.Layout(gtx, theme)
+						})
+					}),
 				)
 			}),
 			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
@@ -0,0 +1,60 @@
+package restful
+
+import (
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+)
+
+// DiffViewer shows the lines of a response diff, additions in green and removals in red.
+type DiffViewer struct {
+	lines []rest.DiffLine
+	list  *widget.List
+}
+
+func NewDiffViewer() *DiffViewer {
+	return &DiffViewer{
+		list: &widget.List{
+			List: layout.List{
+				Axis: layout.Vertical,
+			},
+		},
+	}
+}
+
+func (d *DiffViewer) SetLines(lines []rest.DiffLine) {
+	d.lines = lines
+}
+
+func (d *DiffViewer) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	border := widget.Border{
+		Color:        theme.BorderColor,
+		Width:        unit.Dp(1),
+		CornerRadius: unit.Dp(4),
+	}
+
+	return border.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.UniformInset(3).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return material.List(theme.Material(), d.list).Layout(gtx, len(d.lines), func(gtx layout.Context, i int) layout.Dimensions {
+				line := d.lines[i]
+
+				prefix, color := "  ", theme.TextColor
+				switch line.Kind {
+				case rest.DiffAdded:
+					prefix, color = "+ ", theme.ResponseStatusColor
+				case rest.DiffRemoved:
+					prefix, color = "- ", theme.ErrorColor
+				}
+
+				return layout.Inset{Left: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					l := material.Label(theme.Material(), unit.Sp(14), prefix+line.Text)
+					l.Color = color
+					return l.Layout(gtx)
+				})
+			})
+		})
+	})
+}
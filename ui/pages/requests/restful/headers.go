@@ -3,6 +3,7 @@ package restful
 import (
 	"gioui.org/layout"
 	"gioui.org/unit"
+	"gioui.org/widget"
 	"github.com/chapar-rest/chapar/internal/domain"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 	"github.com/chapar-rest/chapar/ui/converter"
@@ -12,15 +13,27 @@ import (
 type Headers struct {
 	values *widgets.KeyValue
 
+	toggleAllButton widget.Clickable
+	presetsDropDown *widgets.DropDown
+
 	onChange func(values []domain.KeyValue)
 }
 
-func NewHeaders(headers []domain.KeyValue) *Headers {
-	return &Headers{
+func NewHeaders(headers []domain.KeyValue, theme *chapartheme.Theme) *Headers {
+	options := []*widgets.DropDownOption{widgets.NewDropDownOption("Insert header").WithValue("")}
+	for _, p := range domain.HeaderPresets {
+		options = append(options, widgets.NewDropDownOption(p.Key).WithValue(p.Key))
+	}
+
+	h := &Headers{
 		values: widgets.NewKeyValue(
 			converter.WidgetItemsFromKeyValue(headers)...,
 		),
+		presetsDropDown: widgets.NewDropDown(theme, options...),
 	}
+
+	h.presetsDropDown.SetOnChanged(h.insertPreset)
+	return h
 }
 
 func (h *Headers) SetHeaders(headers []domain.KeyValue) {
@@ -35,13 +48,68 @@ func (h *Headers) SetOnChange(f func(values []domain.KeyValue)) {
 	})
 }
 
+// toggleAll enables all the headers, or disables them if they are all already enabled.
+func (h *Headers) toggleAll() {
+	headers := converter.KeyValueFromWidgetItems(h.values.GetItems())
+	domain.SetAllEnabled(headers, !domain.AllEnabled(headers))
+	h.SetHeaders(headers)
+
+	if h.onChange != nil {
+		h.onChange(headers)
+	}
+}
+
+// insertPreset appends the preset with the given key, disabled until the user fills and enables it.
+func (h *Headers) insertPreset(key string) {
+	// the dropdown is only a menu, it always goes back to its title
+	h.presetsDropDown.SetSelected(0)
+
+	for _, p := range domain.HeaderPresets {
+		if p.Key != key {
+			continue
+		}
+
+		headers := append(converter.KeyValueFromWidgetItems(h.values.GetItems()), p.KeyValue())
+		h.SetHeaders(headers)
+
+		if h.onChange != nil {
+			h.onChange(headers)
+		}
+		return
+	}
+}
+
 func (h *Headers) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if h.toggleAllButton.Clicked(gtx) {
+		h.toggleAll()
+	}
+
 	inset := layout.Inset{Top: unit.Dp(15), Right: unit.Dp(10)}
 	return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 		return layout.Flex{
 			Axis:      layout.Vertical,
 			Alignment: layout.Start,
 		}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				title := "Enable all"
+				if domain.AllEnabled(converter.KeyValueFromWidgetItems(h.values.Items)) {
+					title = "Disable all"
+				}
+
+				return layout.Inset{Bottom: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							btn := widgets.Button(theme.Material(), &h.toggleAllButton, nil, widgets.IconPositionStart, title)
+							btn.Color = theme.ButtonTextColor
+							return btn.Layout(gtx, theme)
+						}),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(10)}.Layout),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							return h.presetsDropDown.Layout(gtx, theme)
+						}),
+					)
+				})
+			}),
 			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 				return h.values.WithAddLayout(gtx, "Headers", "", theme)
 			}),
@@ -0,0 +1,159 @@
+package restful
+
+import (
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+type Notes struct {
+	toggle   widget.Clickable
+	expanded bool
+
+	editor widget.Editor
+	tags   *widgets.TextField
+	color  *widgets.DropDown
+
+	onChange      func(description string)
+	onTagsChange  func(tags []string)
+	onColorChange func(color string)
+}
+
+// requestColors are the colors a request can be marked with in the sidebar.
+var requestColors = []struct {
+	name string
+	hex  string
+}{
+	{"Red", "#e53935"},
+	{"Orange", "#fb8c00"},
+	{"Yellow", "#fdd835"},
+	{"Green", "#43a047"},
+	{"Blue", "#1e88e5"},
+	{"Purple", "#8e24aa"},
+}
+
+func NewNotes(theme *chapartheme.Theme, description string, tags []string, color string) *Notes {
+	n := &Notes{
+		// keep the section open when there is something to read
+		expanded: description != "" || len(tags) > 0,
+		tags:     widgets.NewTextField(strings.Join(tags, ", "), "Tags, comma separated (e.g. smoke, wip)"),
+		color:    widgets.NewDropDown(theme),
+	}
+
+	options := []*widgets.DropDownOption{widgets.NewDropDownOption("No color").WithValue("")}
+	for _, c := range requestColors {
+		options = append(options, widgets.NewDropDownOption(c.name).WithValue(c.hex))
+	}
+	n.color.SetOptions(options...)
+	n.color.MinWidth = unit.Dp(120)
+	n.color.SetSelectedByValue(color)
+	n.color.SetOnChanged(func(value string) {
+		if n.onColorChange != nil {
+			n.onColorChange(value)
+		}
+	})
+
+	n.editor.SetText(description)
+	n.tags.SetOnTextChange(func(text string) {
+		if n.onTagsChange != nil {
+			n.onTagsChange(domain.ParseTags(text))
+		}
+	})
+	return n
+}
+
+func (n *Notes) SetOnChange(f func(description string)) {
+	n.onChange = f
+}
+
+func (n *Notes) SetOnTagsChange(f func(tags []string)) {
+	n.onTagsChange = f
+}
+
+func (n *Notes) SetOnColorChange(f func(color string)) {
+	n.onColorChange = f
+}
+
+func (n *Notes) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if n.toggle.Clicked(gtx) {
+		n.expanded = !n.expanded
+	}
+
+	for {
+		event, ok := n.editor.Update(gtx)
+		if !ok {
+			break
+		}
+		if _, ok := event.(widget.ChangeEvent); ok {
+			if n.onChange != nil {
+				n.onChange(n.editor.Text())
+			}
+		}
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return material.Clickable(gtx, &n.toggle, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						icon := widgets.ForwardIcon
+						if n.expanded {
+							icon = widgets.ExpandIcon
+						}
+						gtx.Constraints.Min.X = gtx.Dp(unit.Dp(16))
+						gtx.Constraints.Max.X = gtx.Dp(unit.Dp(16))
+						return icon.Layout(gtx, theme.TextColor)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layout.Inset{Left: unit.Dp(4)}.Layout(gtx, material.Label(theme.Material(), theme.TextSize, "Notes").Layout)
+					}),
+				)
+			})
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if !n.expanded {
+				return layout.Dimensions{}
+			}
+
+			return layout.Inset{Top: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				border := widget.Border{
+					Color:        theme.BorderColor,
+					Width:        unit.Dp(1),
+					CornerRadius: unit.Dp(4),
+				}
+				return border.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Constraints.Max.X
+					gtx.Constraints.Min.Y = gtx.Dp(unit.Dp(60))
+					return layout.UniformInset(unit.Dp(6)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return material.Editor(theme.Material(), &n.editor, "Describe what this request is for").Layout(gtx)
+					})
+				})
+			})
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if !n.expanded {
+				return layout.Dimensions{}
+			}
+
+			return layout.Inset{Top: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Min.X = gtx.Constraints.Max.X
+						return n.tags.Layout(gtx, theme)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layout.Inset{Left: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							return n.color.Layout(gtx, theme)
+						})
+					}),
+				)
+			})
+		}),
+	)
+}
@@ -15,10 +15,11 @@ type Request struct {
 	PreRequest  *component.PrePostRequest
 	PostRequest *component.PrePostRequest
 
-	Body    *Body
-	Params  *Params
-	Headers *Headers
-	Auth    *Auth
+	Body      *Body
+	Params    *Params
+	Headers   *Headers
+	Auth      *Auth
+	Variables *Variables
 }
 
 func NewRequest(req *domain.Request, theme *chapartheme.Theme) *Request {
@@ -28,6 +29,7 @@ func NewRequest(req *domain.Request, theme *chapartheme.Theme) *Request {
 			{Title: "Body"},
 			{Title: "Auth"},
 			{Title: "Headers"},
+			{Title: "Variables"},
 			//	{Title: "Pre Request"},
 			{Title: "Post Request"},
 		}, nil),
@@ -45,16 +47,18 @@ func NewRequest(req *domain.Request, theme *chapartheme.Theme) *Request {
 			//	{Title: "Shell Script", Value: domain.PostRequestTypeShellScript, Type: component.TypeScript, Hint: "Write your post request shell script here"},
 		}, theme),
 
-		Body:    NewBody(req.Spec.HTTP.Request.Body, theme),
-		Params:  NewParams(nil, nil),
-		Headers: NewHeaders(nil),
-		Auth:    NewAuth(req.Spec.HTTP.Request.Auth, theme),
+		Body:      NewBody(req.Spec.HTTP.Request.Body, theme),
+		Params:    NewParams(nil, nil),
+		Headers:   NewHeaders(nil, theme),
+		Auth:      NewAuth(req.Spec.HTTP.Request.Auth, theme),
+		Variables: NewVariables(nil),
 	}
 
 	if req != nil && req.Spec != (domain.RequestSpec{}) && req.Spec.HTTP != nil && req.Spec.HTTP.Request != nil {
 		r.Params.SetQueryParams(req.Spec.HTTP.Request.QueryParams)
 		r.Params.SetPathParams(req.Spec.HTTP.Request.PathParams)
 		r.Headers.SetHeaders(req.Spec.HTTP.Request.Headers)
+		r.Variables.SetVariables(req.Spec.HTTP.Request.Variables)
 
 		//if req.Spec.HTTP.Request.PreRequest != (domain.PreRequest{}) {
 		//	r.PreRequest.SetSelectedDropDown(req.Spec.HTTP.Request.PreRequest.Type)
@@ -94,6 +98,8 @@ func (r *Request) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Di
 					return r.Params.Layout(gtx, theme)
 				case "Headers":
 					return r.Headers.Layout(gtx, theme)
+				case "Variables":
+					return r.Variables.Layout(gtx, theme)
 				case "Auth":
 					return r.Auth.Layout(gtx, theme)
 				case "Body":
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/rest"
 
 	"gioui.org/layout"
 	"gioui.org/unit"
@@ -21,24 +22,53 @@ type Response struct {
 	copyButton *widgets.FlatButton
 	Tabs       *widgets.Tabs
 
-	copyClickable widget.Clickable
+	copyClickable        widget.Clickable
+	copyHeadersClickable widget.Clickable
 
 	responseCode int
 	duration     time.Duration
 	responseSize int
+	// remainingDeadline is how much of the request timeout was left, zero when there is no timeout
+	remainingDeadline time.Duration
+	// traceID is the trace id of the injected traceparent header, empty when none was injected
+	traceID string
 
 	responseHeaders *component.ValuesTable
 	responseCookies *component.ValuesTable
 
-	response string
-	message  string
-	err      error
+	response       string
+	responseIsJSON bool
+	message        string
+	err            error
 
 	onCopyResponse func(gtx layout.Context, response string)
 
 	isResponseUpdated   bool
 	responseIsAvailable bool
 	jsonViewer          *widgets.JsonViewer
+
+	// jsonPathFilter narrows the body down to the value matched by a JSONPath expression
+	jsonPathFilter *widgets.TextField
+
+	// maxRenderBytes caps the size of the body shown, unless the user asked for the full response
+	maxRenderBytes    int
+	isTruncated       bool
+	showFull          bool
+	loadFullClickable widget.Clickable
+
+	// previousResponse is the body of the response before the current one, to diff against
+	previousResponse string
+	hasPrevious      bool
+	isDiffUpdated    bool
+	diffViewer       *DiffViewer
+
+	hasSnapshot       bool
+	snapshotResult    *rest.SnapshotResult
+	snapshotClickable widget.Clickable
+	onUpdateSnapshot  func()
+
+	fixtureClickable widget.Clickable
+	onSaveFixture    func()
 }
 
 func NewResponse(theme *chapartheme.Theme) *Response {
@@ -57,30 +87,111 @@ func NewResponse(theme *chapartheme.Theme) *Response {
 			{Title: "Body"},
 			{Title: "Headers"},
 			{Title: "Cookies"},
+			{Title: "Diff"},
 		}, nil),
 		jsonViewer:      widgets.NewJsonViewer(),
 		responseHeaders: component.NewValuesTable("Headers", nil),
 		responseCookies: component.NewValuesTable("Cookies", nil),
+		jsonPathFilter:  widgets.NewTextField("", "Filter with JSONPath, e.g. $.data[0].id"),
+		diffViewer:      NewDiffViewer(),
 	}
+
+	r.jsonPathFilter.SetOnTextChange(func(text string) {
+		r.isResponseUpdated = false
+	})
 	return r
 }
 
+// filteredResponse returns the response body, or only the part of it matching the JSONPath filter if there is any.
+func (r *Response) filteredResponse(expr string) string {
+	if expr == "" || !r.responseIsJSON {
+		return r.response
+	}
+
+	result, err := rest.ExtractJSONPath(r.response, expr)
+	if err != nil {
+		return err.Error()
+	}
+
+	return result
+}
+
 func (r *Response) SetOnCopyResponse(f func(gtx layout.Context, response string)) {
 	r.onCopyResponse = f
 }
 
 func (r *Response) SetResponse(response string) {
+	if r.responseIsAvailable {
+		r.previousResponse = r.response
+		r.hasPrevious = true
+	}
+
+	r.isDiffUpdated = false
+	r.snapshotResult = nil
+	r.showFull = false
 	r.response = response
+	r.responseIsJSON = rest.IsJSON(response)
 	r.isResponseUpdated = false
 	r.responseIsAvailable = true
 }
 
+func (r *Response) SetMaxRenderBytes(maxBytes int) {
+	r.maxRenderBytes = maxBytes
+	r.isResponseUpdated = false
+}
+
+func (r *Response) SetOnSaveFixture(f func()) {
+	r.onSaveFixture = f
+}
+
+func (r *Response) SetOnUpdateSnapshot(f func()) {
+	r.onUpdateSnapshot = f
+}
+
+func (r *Response) SetHasSnapshot(hasSnapshot bool) {
+	r.hasSnapshot = hasSnapshot
+}
+
+func (r *Response) SetSnapshotResult(result *rest.SnapshotResult) {
+	r.snapshotResult = result
+	r.isDiffUpdated = false
+}
+
+// snapshotFailed reports whether the current response does not match the snapshot,
+// in which case the diff tab shows the changes from the snapshot.
+func (r *Response) snapshotFailed() bool {
+	return r.snapshotResult != nil && r.snapshotResult.Status == rest.SnapshotFailed
+}
+
+func (r *Response) snapshotStatus() string {
+	if r.snapshotResult == nil {
+		return ""
+	}
+
+	switch r.snapshotResult.Status {
+	case rest.SnapshotRecorded:
+		return "Snapshot recorded"
+	case rest.SnapshotPassed:
+		return "Snapshot matches"
+	default:
+		return "Snapshot does not match, see Diff"
+	}
+}
+
 func (r *Response) SetStatusParams(code int, duration time.Duration, size int) {
 	r.responseCode = code
 	r.duration = duration
 	r.responseSize = size
 }
 
+func (r *Response) SetRemainingDeadline(remaining time.Duration) {
+	r.remainingDeadline = remaining
+}
+
+func (r *Response) SetTraceID(traceID string) {
+	r.traceID = traceID
+}
+
 func (r *Response) SetHeaders(headers []domain.KeyValue) {
 	r.responseHeaders.SetData(headers)
 }
@@ -114,6 +225,23 @@ func (r *Response) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.D
 		r.onCopyResponse(gtx, r.response)
 	}
 
+	if r.copyHeadersClickable.Clicked(gtx) {
+		r.onCopyResponse(gtx, rest.CurlHeaders(r.responseHeaders.GetData()))
+	}
+
+	if r.snapshotClickable.Clicked(gtx) && r.onUpdateSnapshot != nil {
+		r.onUpdateSnapshot()
+	}
+
+	if r.fixtureClickable.Clicked(gtx) && r.onSaveFixture != nil {
+		r.onSaveFixture()
+	}
+
+	if r.loadFullClickable.Clicked(gtx) {
+		r.showFull = true
+		r.isResponseUpdated = false
+	}
+
 	inset := layout.Inset{Top: unit.Dp(10)}
 	return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 		return layout.Flex{
@@ -127,7 +255,7 @@ func (r *Response) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.D
 					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
 						return layout.Inset{Left: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 							l := material.LabelStyle{
-								Text:     formatStatus(r.responseCode, r.duration, uint64(r.responseSize)),
+								Text:     formatStatus(r.responseCode, r.duration, uint64(r.responseSize), r.remainingDeadline, r.traceID),
 								Color:    theme.ResponseStatusColor,
 								TextSize: theme.TextSize,
 								Shaper:   theme.Shaper,
@@ -136,6 +264,40 @@ func (r *Response) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.D
 							return l.Layout(gtx)
 						})
 					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						status := r.snapshotStatus()
+						if status == "" {
+							return layout.Dimensions{}
+						}
+
+						return layout.Inset{Right: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							l := material.Label(theme.Material(), theme.TextSize, status)
+							l.Color = theme.ResponseStatusColor
+							if r.snapshotFailed() {
+								l.Color = theme.ErrorColor
+							}
+							return l.Layout(gtx)
+						})
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						title := "Save as snapshot"
+						if r.hasSnapshot {
+							title = "Update snapshot"
+						}
+
+						return layout.Inset{Right: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							btn := widgets.Button(theme.Material(), &r.snapshotClickable, nil, widgets.IconPositionStart, title)
+							btn.Color = theme.ButtonTextColor
+							return btn.Layout(gtx, theme)
+						})
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layout.Inset{Right: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							btn := widgets.Button(theme.Material(), &r.fixtureClickable, nil, widgets.IconPositionStart, "Save as fixture")
+							btn.Color = theme.ButtonTextColor
+							return btn.Layout(gtx, theme)
+						})
+					}),
 					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 						btn := widgets.Button(theme.Material(), &r.copyClickable, widgets.CopyIcon, widgets.IconPositionStart, "Copy")
 						btn.Color = theme.ButtonTextColor
@@ -146,17 +308,75 @@ func (r *Response) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.D
 			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
 				switch r.Tabs.Selected() {
 				case 1:
-					return r.responseHeaders.Layout(gtx, theme)
+					return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							return layout.Inset{Left: unit.Dp(5), Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+								btn := widgets.Button(theme.Material(), &r.copyHeadersClickable, widgets.CopyIcon, widgets.IconPositionStart, "Copy as curl -H")
+								btn.Color = theme.ButtonTextColor
+								return btn.Layout(gtx, theme)
+							})
+						}),
+						layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+							return r.responseHeaders.Layout(gtx, theme)
+						}),
+					)
 				case 2:
 					return r.responseCookies.Layout(gtx, theme)
+				case 3:
+					if !r.hasPrevious && !r.snapshotFailed() {
+						return component.Message(gtx, component.MessageTypeInfo, theme, "Send the request again to compare with this response")
+					}
+
+					if !r.isDiffUpdated {
+						if r.snapshotFailed() {
+							r.diffViewer.SetLines(r.snapshotResult.Diff)
+						} else {
+							r.diffViewer.SetLines(rest.Diff(r.previousResponse, r.response))
+						}
+						r.isDiffUpdated = true
+					}
+
+					return layout.Inset{Left: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return r.diffViewer.Layout(gtx, theme)
+					})
 				default:
 					return layout.Inset{Left: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 						if !r.isResponseUpdated {
-							r.jsonViewer.SetData(r.response)
+							data := r.filteredResponse(r.jsonPathFilter.GetText())
+							r.isTruncated = false
+							if !r.showFull {
+								data, r.isTruncated = rest.TruncateForRender(data, r.maxRenderBytes)
+							}
+
+							r.jsonViewer.SetData(data)
 							r.isResponseUpdated = true
 						}
 
-						return r.jsonViewer.Layout(gtx, theme)
+						return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								if !r.responseIsJSON {
+									return layout.Dimensions{}
+								}
+
+								return layout.Inset{Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+									return r.jsonPathFilter.Layout(gtx, theme)
+								})
+							}),
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								if !r.isTruncated {
+									return layout.Dimensions{}
+								}
+
+								return layout.Inset{Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+									btn := widgets.Button(theme.Material(), &r.loadFullClickable, nil, widgets.IconPositionStart, "Load full response")
+									btn.Color = theme.ButtonTextColor
+									return btn.Layout(gtx, theme)
+								})
+							}),
+							layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+								return r.jsonViewer.Layout(gtx, theme)
+							}),
+						)
 					})
 				}
 			}),
@@ -164,6 +384,13 @@ func (r *Response) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.D
 	})
 }
 
-func formatStatus(statueCode int, duration time.Duration, size uint64) string {
-	return fmt.Sprintf("%d %s, %s, %s", statueCode, http.StatusText(statueCode), duration, humanize.Bytes(size))
+func formatStatus(statueCode int, duration time.Duration, size uint64, remainingDeadline time.Duration, traceID string) string {
+	status := fmt.Sprintf("%d %s, %s, %s", statueCode, http.StatusText(statueCode), duration, humanize.Bytes(size))
+	if remainingDeadline > 0 {
+		status += fmt.Sprintf(", %s left before timeout", remainingDeadline.Round(time.Millisecond))
+	}
+	if traceID != "" {
+		status += ", trace " + traceID
+	}
+	return status
 }
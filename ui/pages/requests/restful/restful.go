@@ -5,6 +5,7 @@ import (
 	"gioui.org/unit"
 	giox "gioui.org/x/component"
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/rest"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 	"github.com/chapar-rest/chapar/ui/pages/requests/component"
 	"github.com/chapar-rest/chapar/ui/widgets"
@@ -17,14 +18,21 @@ type Restful struct {
 
 	Breadcrumb *component.Breadcrumb
 	AddressBar *component.AddressBar
+	Notes      *Notes
 	Response   *Response
 	Request    *Request
 
+	// envDropDown picks the environment the request runs against, the first option follows the selected environment.
+	envDropDown *widgets.DropDown
+
 	split widgets.SplitView
 
-	onSave        func(id string)
-	onDataChanged func(id string, data any)
-	onSubmit      func(id string)
+	onSave           func(id string)
+	onDataChanged    func(id string, data any)
+	onSubmit         func(id string)
+	onCancel         func(id string)
+	onUpdateSnapshot func(id string)
+	onSaveFixture    func(id string)
 }
 
 func New(req *domain.Request, theme *chapartheme.Theme) *Restful {
@@ -33,15 +41,20 @@ func New(req *domain.Request, theme *chapartheme.Theme) *Restful {
 		Prompt:     widgets.NewPrompt("", "", ""),
 		Breadcrumb: component.NewBreadcrumb(req.MetaData.ID, req.CollectionName, req.Spec.HTTP.Method, req.MetaData.Name),
 		AddressBar: component.NewAddressBar(theme, req.Spec.HTTP.URL, req.Spec.HTTP.Method),
+		Notes:      NewNotes(theme, req.MetaData.Description, req.MetaData.Tags, req.MetaData.Color),
 		split: widgets.SplitView{
 			Resize: giox.Resize{
 				Ratio: 0.5,
 			},
 			BarWidth: unit.Dp(2),
 		},
-		Response: NewResponse(theme),
-		Request:  NewRequest(req, theme),
+		Response:    NewResponse(theme),
+		Request:     NewRequest(req, theme),
+		envDropDown: widgets.NewDropDown(theme),
 	}
+	r.envDropDown.MinWidth = unit.Dp(150)
+	r.SetEnvironments(nil)
+	r.Response.SetHasSnapshot(req.Spec.HTTP.Snapshot != nil)
 	r.setupHooks()
 
 	return r
@@ -69,6 +82,74 @@ func (r *Restful) SetOnSubmit(f func(id string)) {
 	r.onSubmit = f
 }
 
+func (r *Restful) SetOnCancel(f func(id string)) {
+	r.onCancel = f
+}
+
+func (r *Restful) SetOnSaveFixture(f func(id string)) {
+	r.onSaveFixture = f
+}
+
+func (r *Restful) SetOnSaveAuthProfile(f func(name string, auth domain.Auth)) {
+	r.Request.Auth.SetOnSaveAsProfile(f)
+}
+
+// SetAuthProfiles lists the auth profiles the request auth can use.
+func (r *Restful) SetAuthProfiles(profiles []*domain.AuthProfile) {
+	r.Request.Auth.SetProfiles(profiles)
+}
+
+func (r *Restful) SetOnUpdateSnapshot(f func(id string)) {
+	r.onUpdateSnapshot = f
+}
+
+// SetSnapshot keeps the snapshot of the edited request in sync after it was recorded or updated.
+func (r *Restful) SetSnapshot(snapshot *domain.ResponseSnapshot) {
+	if snapshot != nil {
+		clone := *snapshot
+		snapshot = &clone
+	}
+
+	r.Req.Spec.HTTP.Snapshot = snapshot
+	r.Response.SetHasSnapshot(snapshot != nil)
+}
+
+func (r *Restful) SetMaxRenderBytes(maxBytes int) {
+	r.Response.SetMaxRenderBytes(maxBytes)
+}
+
+func (r *Restful) SetValidationIssues(issues []rest.ValidationIssue) {
+	messages := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		messages = append(messages, issue.Message)
+	}
+	r.AddressBar.SetIssues(messages)
+}
+
+func (r *Restful) SetSnapshotResult(result *rest.SnapshotResult) {
+	r.Response.SetSnapshotResult(result)
+}
+
+// SetEnvironments lists the environments the request can be run against.
+func (r *Restful) SetEnvironments(envs []*domain.Environment) {
+	options := make([]*widgets.DropDownOption, 0, len(envs)+2)
+	options = append(options, widgets.NewDropDownOption("Selected environment").WithValue(""))
+	options = append(options, widgets.NewDropDownDivider())
+
+	for _, env := range envs {
+		options = append(options, widgets.NewDropDownOption(env.MetaData.Name).WithValue(env.MetaData.ID))
+	}
+
+	r.envDropDown.SetOptions(options...)
+	r.envDropDown.SetSelected(0)
+	r.envDropDown.SetSelectedByValue(r.Req.Spec.HTTP.EnvironmentOverride)
+}
+
+func (r *Restful) SetVariableNames(names func() []string) {
+	r.AddressBar.SetVariableNames(names)
+	r.Request.Body.SetVariableNames(names)
+}
+
 func (r *Restful) SetURL(url string) {
 	r.AddressBar.SetURL(url)
 }
@@ -115,6 +196,8 @@ func (r *Restful) SetHTTPResponse(detail domain.HTTPResponseDetail) {
 	r.Response.SetHeaders(detail.Headers)
 	r.Response.SetCookies(detail.Cookies)
 	r.Response.SetStatusParams(detail.StatusCode, detail.Duration, detail.Size)
+	r.Response.SetRemainingDeadline(detail.RemainingDeadline)
+	r.Response.SetTraceID(detail.TraceID)
 }
 
 func (r *Restful) GetHTTPResponse() *domain.HTTPResponseDetail {
@@ -127,10 +210,12 @@ func (r *Restful) GetHTTPResponse() *domain.HTTPResponseDetail {
 
 func (r *Restful) ShowSendingRequestLoading() {
 	r.Response.SetMessage("Sending request...")
+	r.AddressBar.SetSending(true)
 }
 
 func (r *Restful) HideSendingRequestLoading() {
 	r.Response.SetMessage("")
+	r.AddressBar.SetSending(false)
 }
 
 func (r *Restful) SetOnSave(f func(id string)) {
@@ -171,6 +256,44 @@ func (r *Restful) setupHooks() {
 		r.onSubmit(r.Req.MetaData.ID)
 	})
 
+	r.AddressBar.SetOnCancel(func() {
+		if r.onCancel != nil {
+			r.onCancel(r.Req.MetaData.ID)
+		}
+	})
+
+	r.Response.SetOnSaveFixture(func() {
+		if r.onSaveFixture != nil {
+			r.onSaveFixture(r.Req.MetaData.ID)
+		}
+	})
+
+	r.Response.SetOnUpdateSnapshot(func() {
+		if r.onUpdateSnapshot != nil {
+			r.onUpdateSnapshot(r.Req.MetaData.ID)
+		}
+	})
+
+	r.envDropDown.SetOnChanged(func(envID string) {
+		r.Req.Spec.HTTP.EnvironmentOverride = envID
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Notes.SetOnChange(func(description string) {
+		r.Req.MetaData.Description = description
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Notes.SetOnColorChange(func(color string) {
+		r.Req.MetaData.Color = color
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Notes.SetOnTagsChange(func(tags []string) {
+		r.Req.MetaData.Tags = tags
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
 	r.Request.Params.SetOnChange(func(queryParams []domain.KeyValue, urlParams []domain.KeyValue) {
 		r.Req.Spec.HTTP.Request.QueryParams = queryParams
 		r.Req.Spec.HTTP.Request.PathParams = urlParams
@@ -182,6 +305,11 @@ func (r *Restful) setupHooks() {
 		r.onDataChanged(r.Req.MetaData.ID, r.Req)
 	})
 
+	r.Request.Variables.SetOnChange(func(variables []domain.KeyValue) {
+		r.Req.Spec.HTTP.Request.Variables = variables
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
 	r.Request.Auth.SetOnChange(func(auth domain.Auth) {
 		r.Req.Spec.HTTP.Request.Auth = auth
 		r.onDataChanged(r.Req.MetaData.ID, r.Req)
@@ -213,6 +341,10 @@ func (r *Restful) setupHooks() {
 	})
 }
 
+func (r *Restful) SetSplitAxis(axis layout.Axis) {
+	r.split.Axis = axis
+}
+
 func (r *Restful) SetQueryParams(params []domain.KeyValue) {
 	r.Request.Params.SetQueryParams(params)
 }
@@ -230,7 +362,19 @@ func (r *Restful) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Di
 			}),
 			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 				return layout.Inset{Bottom: unit.Dp(15), Top: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-					return r.Breadcrumb.Layout(gtx, theme)
+					return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+						layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+							return r.Breadcrumb.Layout(gtx, theme)
+						}),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							return r.envDropDown.Layout(gtx, theme)
+						}),
+					)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return r.Notes.Layout(gtx, theme)
 				})
 			}),
 			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
@@ -0,0 +1,44 @@
+package restful
+
+import (
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/converter"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+// Variables are the variables local to the request, they override the environment variables with the same name.
+type Variables struct {
+	values *widgets.KeyValue
+
+	onChange func(values []domain.KeyValue)
+}
+
+func NewVariables(variables []domain.KeyValue) *Variables {
+	return &Variables{
+		values: widgets.NewKeyValue(
+			converter.WidgetItemsFromKeyValue(variables)...,
+		),
+	}
+}
+
+func (v *Variables) SetVariables(variables []domain.KeyValue) {
+	v.values.SetItems(converter.WidgetItemsFromKeyValue(variables))
+}
+
+func (v *Variables) SetOnChange(f func(values []domain.KeyValue)) {
+	v.onChange = f
+
+	v.values.SetOnChanged(func(items []*widgets.KeyValueItem) {
+		v.onChange(converter.KeyValueFromWidgetItems(v.values.Items))
+	})
+}
+
+func (v *Variables) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	inset := layout.Inset{Top: unit.Dp(15), Right: unit.Dp(10)}
+	return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return v.values.WithAddLayout(gtx, "Variables", "", theme)
+	})
+}
@@ -2,6 +2,7 @@ package requests
 
 import (
 	"image"
+	"sync"
 
 	"gioui.org/app"
 	"gioui.org/io/pointer"
@@ -11,6 +12,7 @@ import (
 	"gioui.org/x/component"
 	giox "gioui.org/x/component"
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/rest"
 	"github.com/chapar-rest/chapar/internal/safemap"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 	"github.com/chapar-rest/chapar/ui/keys"
@@ -26,6 +28,11 @@ const (
 	MenuDelete     = "Delete"
 	MenuAddRequest = "Add Request"
 	MenuView       = "View"
+	MenuRepeat     = "Repeat 5 times"
+	MenuMatrix     = "Run in all environments"
+	MenuExportGo   = "Export as Go code"
+	MenuMoveUp     = "Move up"
+	MenuMoveDown   = "Move down"
 )
 
 type View struct {
@@ -48,6 +55,12 @@ type View struct {
 	split     widgets.SplitView
 	tabHeader *widgets.Tabs
 
+	// requestSplitAxis is the axis used to split the request and response panes of the containers
+	requestSplitAxis layout.Axis
+
+	// maxRenderBytes caps the size of the response body shown by the containers
+	maxRenderBytes int
+
 	// callbacks
 	onTitleChanged              func(id, title, containerType string)
 	onNewRequest                func()
@@ -65,6 +78,10 @@ type View struct {
 	onOnPostRequestSetChanged   func(id, item, from, fromKey string)
 	onBinaryFileSelect          func(id string)
 	onFromDataFileSelect        func(requestID, fieldID string)
+	onUpdateSnapshot            func(id string)
+	onSaveFixture               func(id string)
+	onCancel                    func(id string)
+	onSaveAuthProfile           func(name string, auth domain.Auth)
 
 	// state
 	containers    *safemap.Map[Container]
@@ -73,6 +90,10 @@ type View struct {
 	selectedTabId string
 
 	tipsView *tips.Tips
+
+	// uiTasks are queued by RunOnUI from other goroutines and run at the start of the next frame
+	uiTasksMu sync.Mutex
+	uiTasks   []func()
 }
 
 func NewView(w *app.Window, theme *chapartheme.Theme) *View {
@@ -139,7 +160,9 @@ func (v *View) AddRequestTreeViewNode(req *domain.Request) {
 	node := &widgets.TreeNode{
 		Text:        req.MetaData.Name,
 		Identifier:  req.MetaData.ID,
-		MenuOptions: []string{MenuView, MenuDuplicate, MenuDelete},
+		MenuOptions: []string{MenuView, MenuDuplicate, MenuRepeat, MenuMatrix, MenuExportGo, MenuMoveUp, MenuMoveDown, MenuDelete},
+		Tags:        req.MetaData.Tags,
+		Color:       req.MetaData.Color,
 		Meta:        safemap.New[string](),
 	}
 
@@ -153,7 +176,7 @@ func (v *View) AddCollectionTreeViewNode(collection *domain.Collection) {
 		Text:        collection.MetaData.Name,
 		Identifier:  collection.MetaData.ID,
 		Children:    make([]*widgets.TreeNode, 0),
-		MenuOptions: []string{MenuAddRequest, MenuView, MenuDelete},
+		MenuOptions: []string{MenuAddRequest, MenuView, MenuMoveUp, MenuMoveDown, MenuDelete},
 		Meta:        safemap.New[string](),
 	}
 
@@ -171,6 +194,92 @@ func (v *View) RemoveTreeViewNode(id string) {
 	v.treeViewNodes.Delete(id)
 }
 
+func (v *View) SetOnSaveFixture(f func(id string)) {
+	v.onSaveFixture = f
+}
+
+func (v *View) SetOnCancel(f func(id string)) {
+	v.onCancel = f
+}
+
+func (v *View) SetOnSaveAuthProfile(f func(name string, auth domain.Auth)) {
+	v.onSaveAuthProfile = f
+}
+
+func (v *View) SetOnUpdateSnapshot(f func(id string)) {
+	v.onUpdateSnapshot = f
+}
+
+func (v *View) SetSnapshot(id string, snapshot *domain.ResponseSnapshot) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(RestContainer); ok {
+			ct.SetSnapshot(snapshot)
+		}
+	}
+}
+
+func (v *View) SetSnapshotResult(id string, result *rest.SnapshotResult) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(RestContainer); ok {
+			ct.SetSnapshotResult(result)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetEnvironments(id string, envs []*domain.Environment) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(RestContainer); ok {
+			ct.SetEnvironments(envs)
+		}
+	}
+}
+
+// SetAllAuthProfiles refreshes the auth profiles of every open request.
+func (v *View) SetAllAuthProfiles(profiles []*domain.AuthProfile) {
+	for _, ct := range v.containers.Values() {
+		if ct, ok := ct.(RestContainer); ok {
+			ct.SetAuthProfiles(profiles)
+		}
+	}
+	v.window.Invalidate()
+}
+
+func (v *View) SetAuthProfiles(id string, profiles []*domain.AuthProfile) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(RestContainer); ok {
+			ct.SetAuthProfiles(profiles)
+		}
+	}
+}
+
+// SetVariableNames sets where the {{ autocompletion of the request gets its variable names from.
+func (v *View) SetVariableNames(id string, names func() []string) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(RestContainer); ok {
+			ct.SetVariableNames(names)
+		}
+	}
+}
+
+func (v *View) SetValidationIssues(id string, issues []rest.ValidationIssue) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(RestContainer); ok {
+			ct.SetValidationIssues(issues)
+		}
+	}
+}
+
+// SetAllEnvironments refreshes the environments of every open request.
+func (v *View) SetAllEnvironments(envs []*domain.Environment) {
+	for _, ct := range v.containers.Values() {
+		if ct, ok := ct.(RestContainer); ok {
+			ct.SetEnvironments(envs)
+		}
+	}
+	v.window.Invalidate()
+}
+
 func (v *View) SetOnCopyResponse(onCopyResponse func(gtx layout.Context, response string)) {
 	v.onCopyResponse = onCopyResponse
 }
@@ -277,6 +386,19 @@ func (v *View) UpdateTreeNodeTitle(id, title string) {
 	}
 }
 
+func (v *View) UpdateTreeNodeTags(id string, tags []string) {
+	if node, ok := v.treeViewNodes.Get(id); ok {
+		node.Tags = tags
+		v.treeView.Refilter()
+	}
+}
+
+func (v *View) UpdateTreeNodeColor(id, color string) {
+	if node, ok := v.treeViewNodes.Get(id); ok {
+		node.Color = color
+	}
+}
+
 func (v *View) SetTabDirty(id string, dirty bool) {
 	if tab, ok := v.openTabs.Get(id); ok {
 		tab.SetDataChanged(dirty)
@@ -305,6 +427,14 @@ func (v *View) SwitchToTab(id string) {
 	}
 }
 
+// SelectedTabID returns the id of the selected tab, empty when no tab is open.
+func (v *View) SelectedTabID() string {
+	if v.openTabs.Len() == 0 || v.tabHeader.SelectedTab() == nil {
+		return ""
+	}
+	return v.tabHeader.SelectedTab().GetIdentifier()
+}
+
 func (v *View) OpenTab(id, name, tabType string) {
 	tab := &widgets.Tab{
 		Title:          name,
@@ -354,6 +484,8 @@ func (v *View) OpenRequestContainer(req *domain.Request) {
 	}
 
 	ct := restful.New(req, v.theme)
+	ct.SetSplitAxis(v.requestSplitAxis)
+	ct.SetMaxRenderBytes(v.maxRenderBytes)
 	ct.SetOnTitleChanged(func(text string) {
 		if v.onTitleChanged != nil {
 			v.onTitleChanged(req.MetaData.ID, text, TypeRequest)
@@ -402,9 +534,51 @@ func (v *View) OpenRequestContainer(req *domain.Request) {
 		}
 	})
 
+	ct.SetOnSaveFixture(func(id string) {
+		if v.onSaveFixture != nil {
+			v.onSaveFixture(id)
+		}
+	})
+
+	ct.SetOnCancel(func(id string) {
+		if v.onCancel != nil {
+			v.onCancel(id)
+		}
+	})
+
+	ct.SetOnSaveAuthProfile(func(name string, auth domain.Auth) {
+		if v.onSaveAuthProfile != nil {
+			v.onSaveAuthProfile(name, auth)
+		}
+	})
+
+	ct.SetOnUpdateSnapshot(func(id string) {
+		if v.onUpdateSnapshot != nil {
+			v.onUpdateSnapshot(id)
+		}
+	})
+
 	v.containers.Set(req.MetaData.ID, ct)
 }
 
+func (v *View) SetRequestSplitAxis(axis layout.Axis) {
+	v.requestSplitAxis = axis
+	for _, ct := range v.containers.Values() {
+		if ct, ok := ct.(RestContainer); ok {
+			ct.SetSplitAxis(axis)
+		}
+	}
+}
+
+func (v *View) SetMaxRenderBytes(maxBytes int) {
+	v.maxRenderBytes = maxBytes
+	for _, ct := range v.containers.Values() {
+		if ct, ok := ct.(RestContainer); ok {
+			ct.SetMaxRenderBytes(maxBytes)
+		}
+	}
+}
+
 func (v *View) SetSendingRequestLoading(id string) {
 	if ct, ok := v.containers.Get(id); ok {
 		if ct, ok := ct.(RestContainer); ok {
@@ -498,22 +672,28 @@ func (v *View) HidePrompt(id string) {
 }
 
 func (v *View) PopulateTreeView(requests []*domain.Request, collections []*domain.Collection) {
+	domain.SortCollections(collections)
+	domain.SortRequests(requests)
+
 	treeViewNodes := make([]*widgets.TreeNode, 0)
 	for _, cl := range collections {
 		parentNode := &widgets.TreeNode{
 			Text:        cl.MetaData.Name,
 			Identifier:  cl.MetaData.ID,
 			Children:    make([]*widgets.TreeNode, 0),
-			MenuOptions: []string{MenuAddRequest, MenuView, MenuDelete},
+			MenuOptions: []string{MenuAddRequest, MenuView, MenuMoveUp, MenuMoveDown, MenuDelete},
 			Meta:        safemap.New[string](),
 		}
 		parentNode.Meta.Set(TypeMeta, TypeCollection)
 
+		domain.SortRequests(cl.Spec.Requests)
 		for _, req := range cl.Spec.Requests {
 			node := &widgets.TreeNode{
 				Text:        req.MetaData.Name,
 				Identifier:  req.MetaData.ID,
-				MenuOptions: []string{MenuView, MenuDuplicate, MenuDelete},
+				MenuOptions: []string{MenuView, MenuDuplicate, MenuRepeat, MenuMatrix, MenuExportGo, MenuMoveUp, MenuMoveDown, MenuDelete},
+				Tags:        req.MetaData.Tags,
+				Color:       req.MetaData.Color,
 				Meta:        safemap.New[string](),
 			}
 			node.Meta.Set(TypeMeta, TypeRequest)
@@ -529,7 +709,9 @@ func (v *View) PopulateTreeView(requests []*domain.Request, collections []*domai
 		node := &widgets.TreeNode{
 			Text:        req.MetaData.Name,
 			Identifier:  req.MetaData.ID,
-			MenuOptions: []string{MenuView, MenuDuplicate, MenuDelete},
+			MenuOptions: []string{MenuView, MenuDuplicate, MenuRepeat, MenuMatrix, MenuExportGo, MenuMoveUp, MenuMoveDown, MenuDelete},
+			Tags:        req.MetaData.Tags,
+			Color:       req.MetaData.Color,
 			Meta:        safemap.New[string](),
 		}
 		node.Meta.Set(TypeMeta, TypeRequest)
@@ -556,7 +738,9 @@ func (v *View) addTreeViewNode(parentID string, req *domain.Request) {
 	node := &widgets.TreeNode{
 		Text:        req.MetaData.Name,
 		Identifier:  req.MetaData.ID,
-		MenuOptions: []string{MenuDuplicate, MenuDelete},
+		MenuOptions: []string{MenuDuplicate, MenuRepeat, MenuMatrix, MenuExportGo, MenuMoveUp, MenuMoveDown, MenuDelete},
+		Tags:        req.MetaData.Tags,
+		Color:       req.MetaData.Color,
 		Meta:        safemap.New[string](),
 	}
 	node.Meta.Set(TypeMeta, TypeRequest)
@@ -568,7 +752,28 @@ func (v *View) addTreeViewNode(parentID string, req *domain.Request) {
 	v.treeViewNodes.Set(req.MetaData.ID, node)
 }
 
+// RunOnUI queues f to run on the UI goroutine before the next frame is laid out,
+// for changes to the state or the editors made from a goroutine sending a request.
+func (v *View) RunOnUI(f func()) {
+	v.uiTasksMu.Lock()
+	v.uiTasks = append(v.uiTasks, f)
+	v.uiTasksMu.Unlock()
+	v.window.Invalidate()
+}
+
+func (v *View) runUITasks() {
+	v.uiTasksMu.Lock()
+	tasks := v.uiTasks
+	v.uiTasks = nil
+	v.uiTasksMu.Unlock()
+
+	for _, f := range tasks {
+		f()
+	}
+}
+
 func (v *View) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	v.runUITasks()
 	return v.split.Layout(gtx, theme,
 		func(gtx layout.Context) layout.Dimensions {
 			return v.requestList(gtx, theme)
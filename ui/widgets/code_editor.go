@@ -17,8 +17,9 @@ import (
 )
 
 type CodeEditor struct {
-	editor *widget.Editor
-	code   string
+	editor    *widget.Editor
+	completer *VariableCompleter
+	code      string
 
 	lines []string
 	list  *widget.List
@@ -61,6 +62,7 @@ func NewCodeEditor(code string, language string, theme *chapartheme.Theme) *Code
 	c.editor.WrapPolicy = text.WrapGraphemes
 	c.editor.SetText(code)
 	c.lines = strings.Split(code, "\n")
+	c.completer = NewVariableCompleter(c.editor)
 
 	//lexer := lexers.Get(language)
 	//if lexer == nil {
@@ -82,6 +84,11 @@ func (c *CodeEditor) SetOnChanged(f func(text string)) {
 	c.onChange = f
 }
 
+// SetVariableNames sets where the names suggested when typing {{ come from.
+func (c *CodeEditor) SetVariableNames(names func() []string) {
+	c.completer.SetNames(names)
+}
+
 func (c *CodeEditor) SetCode(code string) {
 	c.editor.SetText(code)
 	c.lines = strings.Split(code, "\n")
@@ -102,6 +109,7 @@ func (c *CodeEditor) Code() string {
 
 func (c *CodeEditor) Layout(gtx layout.Context, theme *chapartheme.Theme, hint string) layout.Dimensions {
 	// c.handleThemeChange(theme)
+	c.completer.Update(gtx)
 
 	for {
 		ev, ok := gtx.Event(
@@ -135,6 +143,21 @@ func (c *CodeEditor) Layout(gtx layout.Context, theme *chapartheme.Theme, hint s
 		}
 	}
 
+	if !c.completer.Visible() {
+		return c.layoutEditor(gtx, theme, hint)
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return c.completer.Layout(gtx, theme)
+		}),
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return c.layoutEditor(gtx, theme, hint)
+		}),
+	)
+}
+
+func (c *CodeEditor) layoutEditor(gtx layout.Context, theme *chapartheme.Theme, hint string) layout.Dimensions {
 	flexH := layout.Flex{Axis: layout.Horizontal}
 	listInset := layout.Inset{Left: unit.Dp(10), Top: unit.Dp(4)}
 	inset4 := layout.UniformInset(unit.Dp(4))
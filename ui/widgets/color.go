@@ -1,6 +1,25 @@
 package widgets
 
-import "image/color"
+import (
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// ParseHexColor parses a #rrggbb color, it reports false for anything else.
+func ParseHexColor(s string) (color.NRGBA, bool) {
+	s, ok := strings.CutPrefix(s, "#")
+	if !ok || len(s) != 6 {
+		return color.NRGBA{}, false
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, false
+	}
+
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, true
+}
 
 // MulAlpha applies the alpha to the color.
 func MulAlpha(c color.NRGBA, alpha uint8) color.NRGBA {
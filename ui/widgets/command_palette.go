@@ -0,0 +1,210 @@
+package widgets
+
+import (
+	"image"
+
+	"gioui.org/io/key"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/palette"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+)
+
+// CommandPalette is an overlay listing commands filtered by what is typed, Enter runs the selected one.
+type CommandPalette struct {
+	visible   bool
+	justShown bool
+
+	palette *palette.Palette
+	editor  widget.Editor
+	list    widget.List
+
+	clickables []widget.Clickable
+
+	// commands builds the commands every time the palette is shown, so they reflect the current data
+	commands func() []palette.Command
+}
+
+func NewCommandPalette(commands func() []palette.Command) *CommandPalette {
+	c := &CommandPalette{
+		palette:  palette.New(nil),
+		commands: commands,
+		list: widget.List{
+			List: layout.List{
+				Axis: layout.Vertical,
+			},
+		},
+	}
+
+	c.editor.SingleLine = true
+	c.editor.Submit = true
+	return c
+}
+
+func (c *CommandPalette) Show() {
+	c.palette.SetCommands(c.commands())
+	c.editor.SetText("")
+	c.palette.SetQuery("")
+	c.visible = true
+	c.justShown = true
+}
+
+func (c *CommandPalette) Hide() {
+	c.visible = false
+}
+
+func (c *CommandPalette) IsVisible() bool {
+	return c.visible
+}
+
+func (c *CommandPalette) execute() {
+	c.Hide()
+	c.palette.Execute()
+}
+
+func (c *CommandPalette) update(gtx layout.Context) {
+	if c.justShown {
+		gtx.Execute(key.FocusCmd{Tag: &c.editor})
+		c.justShown = false
+	}
+
+	for {
+		ev, ok := gtx.Event(
+			key.Filter{Focus: &c.editor, Name: key.NameUpArrow},
+			key.Filter{Focus: &c.editor, Name: key.NameDownArrow},
+			key.Filter{Focus: &c.editor, Name: key.NameEscape},
+		)
+		if !ok {
+			break
+		}
+
+		e, ok := ev.(key.Event)
+		if !ok || e.State != key.Press {
+			continue
+		}
+
+		switch e.Name {
+		case key.NameUpArrow:
+			c.palette.Move(-1)
+			c.list.ScrollTo(c.palette.Selected())
+		case key.NameDownArrow:
+			c.palette.Move(1)
+			c.list.ScrollTo(c.palette.Selected())
+		case key.NameEscape:
+			c.Hide()
+		}
+	}
+
+	for {
+		ev, ok := c.editor.Update(gtx)
+		if !ok {
+			break
+		}
+
+		switch ev.(type) {
+		case widget.ChangeEvent:
+			c.palette.SetQuery(c.editor.Text())
+			c.list.ScrollTo(0)
+		case widget.SubmitEvent:
+			c.execute()
+		}
+	}
+
+	results := c.palette.Results()
+	if len(c.clickables) < len(results) {
+		c.clickables = make([]widget.Clickable, len(results))
+	}
+
+	for i := range results {
+		if c.clickables[i].Clicked(gtx) {
+			c.palette.Select(i)
+			c.execute()
+			break
+		}
+	}
+}
+
+func (c *CommandPalette) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if !c.visible {
+		return layout.Dimensions{}
+	}
+
+	c.update(gtx)
+	if !c.visible {
+		return layout.Dimensions{}
+	}
+
+	return layout.N.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Inset{Top: unit.Dp(60)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min.X = gtx.Dp(500)
+			gtx.Constraints.Max.X = gtx.Constraints.Min.X
+			gtx.Constraints.Max.Y = min(gtx.Constraints.Max.Y, gtx.Dp(400))
+
+			return layout.Background{}.Layout(gtx,
+				func(gtx layout.Context) layout.Dimensions {
+					defer clip.UniformRRect(image.Rectangle{Max: gtx.Constraints.Min}, gtx.Dp(6)).Push(gtx.Ops).Pop()
+					paint.Fill(gtx.Ops, theme.DropDownMenuBgColor)
+					return layout.Dimensions{Size: gtx.Constraints.Min}
+				},
+				func(gtx layout.Context) layout.Dimensions {
+					return layout.UniformInset(unit.Dp(8)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								border := widget.Border{Color: theme.BorderColorFocused, Width: unit.Dp(1), CornerRadius: unit.Dp(4)}
+								return border.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+									return layout.UniformInset(unit.Dp(6)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+										e := material.Editor(theme.Material(), &c.editor, "Type a command or a request name")
+										e.Color = chapartheme.White
+										return e.Layout(gtx)
+									})
+								})
+							}),
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								return layout.Inset{Top: unit.Dp(6)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+									return c.layoutResults(gtx, theme)
+								})
+							}),
+						)
+					})
+				},
+			)
+		})
+	})
+}
+
+func (c *CommandPalette) layoutResults(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	results := c.palette.Results()
+	if len(results) == 0 {
+		l := material.Label(theme.Material(), theme.TextSize, "No matching commands")
+		l.Color = chapartheme.White
+		return layout.UniformInset(unit.Dp(6)).Layout(gtx, l.Layout)
+	}
+
+	return material.List(theme.Material(), &c.list).Layout(gtx, len(results), func(gtx layout.Context, i int) layout.Dimensions {
+		return c.clickables[i].Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min.X = gtx.Constraints.Max.X
+			return layout.Background{}.Layout(gtx,
+				func(gtx layout.Context) layout.Dimensions {
+					if i != c.palette.Selected() && !c.clickables[i].Hovered() {
+						return layout.Dimensions{Size: gtx.Constraints.Min}
+					}
+
+					defer clip.UniformRRect(image.Rectangle{Max: gtx.Constraints.Min}, gtx.Dp(4)).Push(gtx.Ops).Pop()
+					paint.Fill(gtx.Ops, theme.TextSelectionColor)
+					return layout.Dimensions{Size: gtx.Constraints.Min}
+				},
+				func(gtx layout.Context) layout.Dimensions {
+					return layout.UniformInset(unit.Dp(6)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						l := material.Label(theme.Material(), theme.TextSize, results[i].Title)
+						l.Color = chapartheme.White
+						return l.Layout(gtx)
+					})
+				},
+			)
+		})
+	})
+}
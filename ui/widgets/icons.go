@@ -55,6 +55,11 @@ var SwapHoriz *widget.Icon = func() *widget.Icon {
 	return icon
 }()
 
+var SwapVert *widget.Icon = func() *widget.Icon {
+	icon, _ := widget.NewIcon(icons.ActionSwapVert)
+	return icon
+}()
+
 var SettingsIcon *widget.Icon = func() *widget.Icon {
 	icon, _ := widget.NewIcon(icons.ActionSettings)
 	return icon
@@ -41,6 +41,14 @@ func (s *SplitView) Layout(gtx layout.Context, theme *chapartheme.Theme, left, r
 					Y: gtx.Constraints.Max.Y,
 				},
 			}
+
+			if s.Axis == layout.Vertical {
+				rect.Max = image.Point{
+					X: gtx.Constraints.Max.X,
+					Y: gtx.Dp(unit.Dp(2)),
+				}
+			}
+
 			paint.FillShape(gtx.Ops, theme.SeparatorColor, clip.Rect(rect).Op())
 			return layout.Dimensions{Size: rect.Max}
 		},
@@ -47,6 +47,10 @@ func (t *TextField) SetText(text string) {
 	t.textEditor.SetText(text)
 }
 
+func (t *TextField) GetText() string {
+	return t.textEditor.Text()
+}
+
 func (t *TextField) SetIcon(icon *widget.Icon, position int) {
 	t.Icon = icon
 	t.IconPosition = position
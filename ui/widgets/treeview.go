@@ -17,6 +17,7 @@ import (
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 	"gioui.org/x/component"
+	"github.com/chapar-rest/chapar/internal/domain"
 	"github.com/chapar-rest/chapar/internal/safemap"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 )
@@ -40,6 +41,9 @@ type TreeNode struct {
 	Children       []*TreeNode
 	DiscloserState component.DiscloserState
 	MenuOptions    []string
+	Tags           []string
+	// Color is a #rrggbb color drawn as a swatch before the text
+	Color string
 
 	menuContextArea component.ContextArea
 	menu            component.MenuState
@@ -143,14 +147,24 @@ func (t *TreeView) Filter(text string) {
 		return
 	}
 
+	// "#tag" queries match nodes by tag instead of by title
+	match := func(node *TreeNode) bool {
+		return strings.Contains(node.Text, text)
+	}
+	if tag, ok := strings.CutPrefix(text, "#"); ok {
+		match = func(node *TreeNode) bool {
+			return node.hasTag(tag)
+		}
+	}
+
 	var items = make([]*TreeNode, 0)
 	for _, item := range t.nodes {
-		if strings.Contains(item.Text, text) {
+		if match(item) {
 			items = append(items, item)
 		}
 
 		for _, child := range item.Children {
-			if strings.Contains(child.Text, text) {
+			if match(child) {
 				items = append(items, child)
 			}
 		}
@@ -159,6 +173,15 @@ func (t *TreeView) Filter(text string) {
 	t.filteredNodes = items
 }
 
+// Refilter applies the current filter again, e.g. after node tags changed.
+func (t *TreeView) Refilter() {
+	t.Filter(t.filterText)
+}
+
+func (tr *TreeNode) hasTag(tag string) bool {
+	return domain.ContainsTag(tr.Tags, tag)
+}
+
 func (t *TreeView) clickableWrap(gtx layout.Context, theme *chapartheme.Theme, node *TreeNode, widget layout.Widget) layout.Dimensions {
 	return node.DiscloserState.Clickable.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 		return layout.Background{}.Layout(gtx,
@@ -234,6 +257,19 @@ func (t *TreeView) itemLayout(gtx layout.Context, theme *chapartheme.Theme, node
 	return t.clickableWrap(gtx, theme, node, func(gtx layout.Context) layout.Dimensions {
 		return layout.Inset{Top: unit.Dp(8), Bottom: unit.Dp(8), Left: unit.Dp(8 + leftPadding)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					swatch, ok := ParseHexColor(node.Color)
+					if !ok {
+						return layout.Dimensions{}
+					}
+
+					return layout.Inset{Right: unit.Dp(6)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						size := gtx.Dp(unit.Dp(8))
+						defer clip.Ellipse{Max: image.Pt(size, size)}.Push(gtx.Ops).Pop()
+						paint.Fill(gtx.Ops, swatch)
+						return layout.Dimensions{Size: image.Pt(size, size)}
+					})
+				}),
 				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
 					lb := material.Label(theme.Material(), unit.Sp(13), node.Text)
 					lb.Font.Weight = font.SemiBold
@@ -0,0 +1,134 @@
+package widgets
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"gioui.org/io/key"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/completion"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+)
+
+const maxVariableSuggestions = 8
+
+// VariableCompleter suggests variable names while a {{variable}} is typed in an editor,
+// clicking a suggestion completes it.
+type VariableCompleter struct {
+	editor *widget.Editor
+
+	// names returns the variable names every time the text changes, so they reflect the current data
+	names func() []string
+
+	lastText  string
+	lastCaret int
+
+	prefix     string
+	matches    []string
+	clickables []widget.Clickable
+}
+
+func NewVariableCompleter(editor *widget.Editor) *VariableCompleter {
+	return &VariableCompleter{
+		editor:    editor,
+		lastCaret: -1,
+	}
+}
+
+func (v *VariableCompleter) SetNames(names func() []string) {
+	v.names = names
+	v.lastCaret = -1
+}
+
+// Update completes the variable when a suggestion was clicked, it is called before the editor
+// handles its events so the change is reported in the same frame.
+func (v *VariableCompleter) Update(gtx layout.Context) {
+	for i := range v.matches {
+		if i < len(v.clickables) && v.clickables[i].Clicked(gtx) {
+			v.complete(v.matches[i])
+			gtx.Execute(key.FocusCmd{Tag: v.editor})
+			break
+		}
+	}
+}
+
+func (v *VariableCompleter) complete(name string) {
+	_, caret := v.editor.Selection()
+	start := caret - utf8.RuneCountInString(v.prefix)
+	if start < 0 {
+		return
+	}
+
+	insert := name
+	if rest := []rune(v.editor.Text())[caret:]; !strings.HasPrefix(string(rest), "}}") {
+		insert += "}}"
+	}
+
+	// replace what was typed, as names are matched ignoring case
+	v.editor.SetCaret(start, caret)
+	v.editor.Insert(insert)
+	v.matches = nil
+}
+
+func (v *VariableCompleter) refresh() {
+	text := v.editor.Text()
+	_, caret := v.editor.Selection()
+	if text == v.lastText && caret == v.lastCaret {
+		return
+	}
+
+	v.lastText, v.lastCaret = text, caret
+	v.matches = nil
+
+	if v.names == nil || v.editor.SelectionLen() > 0 {
+		return
+	}
+
+	prefix, ok := completion.VariablePrefix(text, caret)
+	if !ok {
+		return
+	}
+
+	v.prefix = prefix
+	v.matches = completion.Match(v.names(), prefix)
+	if len(v.matches) > maxVariableSuggestions {
+		v.matches = v.matches[:maxVariableSuggestions]
+	}
+
+	if len(v.clickables) < len(v.matches) {
+		v.clickables = make([]widget.Clickable, len(v.matches))
+	}
+}
+
+// Visible reports whether there are suggestions for what is being typed.
+func (v *VariableCompleter) Visible() bool {
+	v.refresh()
+	return len(v.matches) > 0
+}
+
+func (v *VariableCompleter) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if !v.Visible() {
+		return layout.Dimensions{}
+	}
+
+	children := make([]layout.FlexChild, 0, len(v.matches))
+	for i, name := range v.matches {
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Right: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				btn := material.Button(theme.Material(), &v.clickables[i], name)
+				btn.Background = theme.Palette.ContrastBg
+				btn.Color = theme.Palette.ContrastFg
+				btn.TextSize = unit.Sp(12)
+				btn.Inset = layout.UniformInset(unit.Dp(4))
+				return btn.Layout(gtx)
+			})
+		}))
+	}
+
+	return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal}.Layout(gtx, children...)
+	})
+}